@@ -1,107 +1,580 @@
 // CLASSIFICATION: COMMUNITY
-// Filename: mux.go v0.1
-// Date Modified: 2025-06-07
+// Filename: mux.go v0.3
+// Date Modified: 2026-07-26
 // Author: Lukas Bower
 //
-// Simple 9P multiplexer helper used by integration tests. It waits on
-// multiple service channels and forwards requests to the Cohesix runtime.
+// Package p9 provides a concurrent 9P-style request multiplexer. Requests
+// are routed to registered handlers through a per-segment route tree
+// supporting literal, :param, and *wildcard pattern segments, and
+// dispatched through a condition-variable-backed work queue instead of
+// busy-polling, with per-request tags so concurrent Do calls demultiplex
+// correctly -- mirroring 9P's own T/R message tags.
 package p9
 
 import (
-        "context"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
 )
 
+// Request is a single 9P-style call, as seen by a SimpleHandler.
 type Request struct {
-        Path string
-        Data []byte
+	Path string
+	Data []byte
+}
+
+// Response is the result of handling a Request. Tag echoes the value Do
+// assigned the call, mirroring how a 9P R-message tag echoes its T-message's.
+type Response struct {
+	Data []byte
+	Tag  uint64
 }
 
-type ServiceChan <-chan Request
+// Context is the per-request routing context passed to a Handler. It
+// embeds context.Context so handlers participate in cancellation and
+// deadlines exactly as a plain context.Context would, while also exposing
+// the pattern captures Do's routing produced.
+type Context interface {
+	context.Context
 
-type Mux struct {
-        services map[string]ServiceChan
+	// Path returns the raw path passed to Do, unmodified by routing.
+	Path() string
+	// Param returns the value captured for a :name or *name pattern
+	// segment of the matched route, or "" if name wasn't part of it.
+	Param(name string) string
 }
 
-func NewMux() *Mux {
-        return &Mux{services: make(map[string]ServiceChan)}
+type muxContext struct {
+	context.Context
+	path   string
+	params map[string]string
 }
 
-func (m *Mux) Register(name string, ch ServiceChan) {
-        m.services[name] = ch
+func (c *muxContext) Path() string { return c.path }
+
+func (c *muxContext) Param(name string) string { return c.params[name] }
+
+// Handler handles a request matched against a registered pattern.
+type Handler interface {
+	Handle(ctx Context, data []byte) ([]byte, error)
 }
 
-func (m *Mux) Serve(ctx context.Context) {
-        for {
-                select {
-                case <-ctx.Done():
-                        return
-                default:
-                        for name, ch := range m.services {
-                                select {
-                                case req := <-ch:
-                                        _ = req
-                                        // In a real implementation this would translate to 9P calls.
-                                default:
-                                }
-                                _ = name
-                        }
-                }
-        }
-}
-
-
-// Package p9 provides a simple concurrent request multiplexer that
-// mirrors the behaviour of the Rust counterpart.  Each service is
-// identified by name and implements the Handler interface.
-package p9
+// HandlerFunc adapts a plain function to a Handler.
+type HandlerFunc func(ctx Context, data []byte) ([]byte, error)
 
-import (
-	"sync"
+// Handle calls f.
+func (f HandlerFunc) Handle(ctx Context, data []byte) ([]byte, error) {
+	return f(ctx, data)
+}
+
+// SimpleHandler is p9's original Handler shape, from before patterns
+// carried named captures: it only ever saw the raw path, with no way to
+// tell a :param from the rest of the path. Register still accepts one
+// through AdaptSimpleHandler so callers that don't need captures aren't
+// forced to adopt Context.
+type SimpleHandler interface {
+	Handle(ctx context.Context, req Request) (Response, error)
+}
+
+// SimpleHandlerFunc adapts a plain function to a SimpleHandler.
+type SimpleHandlerFunc func(ctx context.Context, req Request) (Response, error)
+
+// Handle calls f.
+func (f SimpleHandlerFunc) Handle(ctx context.Context, req Request) (Response, error) {
+	return f(ctx, req)
+}
+
+// AdaptSimpleHandler wraps a SimpleHandler as a Handler. The adapted
+// handler sees the full raw path, since SimpleHandler has no notion of
+// parameter capture to narrow it down to -- matching how it behaved before
+// patterns existed, when every registered prefix was effectively the
+// whole route.
+func AdaptSimpleHandler(h SimpleHandler) Handler {
+	return HandlerFunc(func(ctx Context, data []byte) ([]byte, error) {
+		resp, err := h.Handle(ctx, Request{Path: ctx.Path(), Data: data})
+		if err != nil {
+			return nil, err
+		}
+		return resp.Data, nil
+	})
+}
+
+// Middleware wraps a Handler to layer cross-cutting concerns (authorization,
+// tracing, ...) in front of it. Middlewares registered via Use run in the
+// order they were added, outermost first.
+type Middleware func(Handler) Handler
+
+// EventType distinguishes route registration from deregistration in a
+// Watch stream.
+type EventType int
+
+const (
+	// EventAdded is emitted when a pattern is registered.
+	EventAdded EventType = iota
+	// EventRemoved is emitted when a pattern is deregistered.
+	EventRemoved
 )
 
-// Handler represents a service capable of handling a 9P path.
-type Handler interface {
-	Handle(path string, data []byte) ([]byte, error)
+// Event describes a route change observed through Watch.
+type Event struct {
+	Type EventType
+	Path string
+}
+
+// CancelFunc stops a Watch subscription and closes its event channel.
+type CancelFunc func()
+
+type doResult struct {
+	resp Response
+	err  error
+}
+
+type job struct {
+	ctx     context.Context
+	tag     uint64
+	path    string
+	params  map[string]string
+	data    []byte
+	handler Handler
+	result  chan doResult
 }
 
-// Mux routes requests to registered handlers.
+// Mux routes requests to registered handlers by matching a compiled
+// pattern tree.
 type Mux struct {
-	mu       sync.RWMutex
-	services map[string]Handler
+	mu         sync.RWMutex
+	tree       *routeTree
+	middleware []Middleware
+
+	qmu    sync.Mutex
+	qcond  *sync.Cond
+	queue  []*job
+	closed bool
+
+	tagSeq uint64
+
+	watchMu  sync.Mutex
+	watchers map[string]map[chan Event]struct{}
 }
 
 // NewMux returns a ready-to-use multiplexer.
 func NewMux() *Mux {
-	return &Mux{services: make(map[string]Handler)}
+	m := &Mux{
+		tree:     newRouteTree(),
+		watchers: make(map[string]map[chan Event]struct{}),
+	}
+	m.qcond = sync.NewCond(&m.qmu)
+	return m
 }
 
-// Register adds a named service.
-func (m *Mux) Register(name string, h Handler) {
+// Use appends middleware to the chain applied to every Do call. Middleware
+// added after a call to Do is already in flight does not affect that call.
+func (m *Mux) Use(mw ...Middleware) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.services[name] = h
+	m.middleware = append(m.middleware, mw...)
 }
 
-// Handle routes the request in a goroutine and returns a channel with the response.
-func (m *Mux) Handle(path string, data []byte) <-chan []byte {
-	ch := make(chan []byte, 1)
-	go func() {
-		m.mu.RLock()
-		var h Handler
-		for prefix, handler := range m.services {
-			if len(path) >= len(prefix) && path[:len(prefix)] == prefix {
-				h = handler
-				path = path[len(prefix):]
-				break
+// Register compiles pattern (a path of literal, :param, and *wildcard
+// segments) into the route tree and installs h for it, replacing any
+// existing handler registered for the exact same pattern text. It returns
+// an error if pattern is malformed, or if it conflicts with an already
+// registered pattern ambiguously -- e.g. a :vol and a :disk segment both
+// claiming the same position, which the tree has no deterministic way to
+// pick between. Register notifies Watch subscribers of pattern on success.
+func (m *Mux) Register(pattern string, h Handler) error {
+	segs, err := parsePattern(pattern)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	err = m.tree.insert(segs, pattern, h)
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	m.notify(Event{Type: EventAdded, Path: pattern})
+	return nil
+}
+
+// Deregister removes the handler registered for the exact pattern text, if
+// any, and notifies Watch subscribers.
+func (m *Mux) Deregister(pattern string) {
+	segs, err := parsePattern(pattern)
+	if err != nil {
+		return
+	}
+
+	m.mu.Lock()
+	removed := m.tree.remove(segs)
+	m.mu.Unlock()
+	if removed {
+		m.notify(Event{Type: EventRemoved, Path: pattern})
+	}
+}
+
+// Do performs an RPC-style call: it matches path against the route tree,
+// assigns the request a tag (mirroring a 9P T-message tag) so it can be
+// demultiplexed from concurrent calls, and blocks until a dispatch worker
+// delivers a Response or ctx is done.
+func (m *Mux) Do(ctx context.Context, path string, data []byte) (Response, error) {
+	m.mu.RLock()
+	handler, params, _, ok := m.tree.lookup(path)
+	wrapped := m.wrapLocked(handler)
+	m.mu.RUnlock()
+	if !ok {
+		return Response{}, fmt.Errorf("p9: no handler registered for %q", path)
+	}
+	tag := atomic.AddUint64(&m.tagSeq, 1)
+
+	j := &job{
+		ctx:     ctx,
+		tag:     tag,
+		path:    path,
+		params:  params,
+		data:    data,
+		handler: wrapped,
+		result:  make(chan doResult, 1),
+	}
+	m.enqueue(j)
+
+	select {
+	case <-ctx.Done():
+		return Response{}, ctx.Err()
+	case res := <-j.result:
+		return res.resp, res.err
+	}
+}
+
+// Watch subscribes to Register/Deregister events for patterns under
+// prefix.  The returned channel is closed once CancelFunc is called or ctx
+// is done.
+func (m *Mux) Watch(ctx context.Context, prefix string) (<-chan Event, CancelFunc) {
+	ch := make(chan Event, 16)
+
+	m.watchMu.Lock()
+	set, ok := m.watchers[prefix]
+	if !ok {
+		set = make(map[chan Event]struct{})
+		m.watchers[prefix] = set
+	}
+	set[ch] = struct{}{}
+	m.watchMu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			m.watchMu.Lock()
+			delete(m.watchers[prefix], ch)
+			if len(m.watchers[prefix]) == 0 {
+				delete(m.watchers, prefix)
 			}
+			m.watchMu.Unlock()
+			close(ch)
+		})
+	}
+
+	go func() {
+		<-ctx.Done()
+		cancel()
+	}()
+
+	return ch, cancel
+}
+
+// Serve runs the dispatch loop until ctx is done. Workers block on the work
+// queue's condition variable rather than busy-polling, waking only when Do
+// enqueues a job or Serve is asked to shut down.
+func (m *Mux) Serve(ctx context.Context) {
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-stop:
 		}
-		m.mu.RUnlock()
-		if h == nil {
-			ch <- []byte("error: service not found")
+		m.qmu.Lock()
+		m.closed = true
+		m.qcond.Broadcast()
+		m.qmu.Unlock()
+	}()
+	defer close(stop)
+
+	for {
+		j := m.dequeue()
+		if j == nil {
 			return
 		}
-		resp, _ := h.Handle(path, data)
-		ch <- resp
-	}()
-	return ch
+		go m.run(j)
+	}
+}
+
+func (m *Mux) run(j *job) {
+	pctx := &muxContext{Context: j.ctx, path: j.path, params: j.params}
+	data, err := j.handler.Handle(pctx, j.data)
+	select {
+	case j.result <- doResult{resp: Response{Data: data, Tag: j.tag}, err: err}:
+	default:
+		// Do already returned via ctx.Done(); drop the late result.
+	}
+}
+
+func (m *Mux) enqueue(j *job) {
+	m.qmu.Lock()
+	m.queue = append(m.queue, j)
+	m.qcond.Signal()
+	m.qmu.Unlock()
+}
+
+func (m *Mux) dequeue() *job {
+	m.qmu.Lock()
+	defer m.qmu.Unlock()
+	for len(m.queue) == 0 && !m.closed {
+		m.qcond.Wait()
+	}
+	if len(m.queue) == 0 {
+		return nil
+	}
+	j := m.queue[0]
+	m.queue = m.queue[1:]
+	return j
+}
+
+// wrapLocked layers the middleware chain around handler. Callers must hold
+// at least m.mu's read lock.
+func (m *Mux) wrapLocked(h Handler) Handler {
+	for i := len(m.middleware) - 1; i >= 0; i-- {
+		h = m.middleware[i](h)
+	}
+	return h
+}
+
+func (m *Mux) notify(ev Event) {
+	m.watchMu.Lock()
+	defer m.watchMu.Unlock()
+	for prefix, set := range m.watchers {
+		if !strings.HasPrefix(ev.Path, prefix) {
+			continue
+		}
+		for ch := range set {
+			select {
+			case ch <- ev:
+			default:
+			}
+		}
+	}
+}
+
+// segKind distinguishes the three pattern segment shapes Register
+// understands.
+type segKind int
+
+const (
+	segLiteral segKind = iota
+	segParam
+	segWildcard
+)
+
+// segment is one "/"-delimited piece of a compiled pattern. text is the
+// literal value for segLiteral, or the capture name (without its : or *
+// sigil) for segParam/segWildcard.
+type segment struct {
+	kind segKind
+	text string
+}
+
+// parsePattern splits pattern into segments, validating that every :param
+// and *wildcard segment is named and that *wildcard (which captures
+// everything remaining, including slashes) only ever appears last.
+func parsePattern(pattern string) ([]segment, error) {
+	if !strings.HasPrefix(pattern, "/") {
+		return nil, fmt.Errorf("p9: pattern %q must be absolute", pattern)
+	}
+	parts := splitSegments(pattern)
+	segs := make([]segment, len(parts))
+	for i, part := range parts {
+		switch {
+		case strings.HasPrefix(part, ":"):
+			name := part[1:]
+			if name == "" {
+				return nil, fmt.Errorf("p9: pattern %q has an unnamed :param segment", pattern)
+			}
+			segs[i] = segment{kind: segParam, text: name}
+		case strings.HasPrefix(part, "*"):
+			name := part[1:]
+			if name == "" {
+				return nil, fmt.Errorf("p9: pattern %q has an unnamed *wildcard segment", pattern)
+			}
+			if i != len(parts)-1 {
+				return nil, fmt.Errorf("p9: pattern %q: *%s must be the last segment", pattern, name)
+			}
+			segs[i] = segment{kind: segWildcard, text: name}
+		default:
+			segs[i] = segment{kind: segLiteral, text: part}
+		}
+	}
+	return segs, nil
+}
+
+// splitSegments splits an absolute path (pattern or request path) into its
+// "/"-delimited pieces, treating "/" itself as a single empty-string
+// segment so the root pattern and root request path compile and match
+// identically.
+func splitSegments(path string) []string {
+	trimmed := strings.TrimPrefix(path, "/")
+	return strings.Split(trimmed, "/")
+}
+
+// routeTree is the compiled form of every pattern Register has installed:
+// a trie over path segments rather than a byte-compressed radix tree --
+// route counts here are in the tens, not thousands, so edge compression
+// buys nothing, only complexity.
+type routeTree struct {
+	root *routeNode
+}
+
+// routeNode is one segment position in the tree. children holds literal
+// segment matches; paramChild/wildcardHandler hold at most one :param and
+// one *wildcard continuation respectively, since Register rejects a second
+// one under a different name at the same position.
+type routeNode struct {
+	children map[string]*routeNode
+
+	paramChild *routeNode
+	paramName  string
+
+	wildcardName    string
+	wildcardHandler Handler
+	wildcardPattern string
+
+	handler Handler
+	pattern string
+}
+
+func newRouteTree() *routeTree {
+	return &routeTree{root: &routeNode{children: make(map[string]*routeNode)}}
+}
+
+// insert installs h at the position segs describes, creating intermediate
+// nodes as needed. It returns an error if segs conflicts with an existing
+// :param or *wildcard under a different capture name at the same position;
+// re-registering the exact same pattern text instead replaces the
+// existing handler, matching Mux's original Register semantics.
+func (t *routeTree) insert(segs []segment, pattern string, h Handler) error {
+	node := t.root
+	for i, seg := range segs {
+		switch seg.kind {
+		case segLiteral:
+			child, ok := node.children[seg.text]
+			if !ok {
+				child = &routeNode{children: make(map[string]*routeNode)}
+				node.children[seg.text] = child
+			}
+			node = child
+		case segParam:
+			if node.paramChild == nil {
+				node.paramChild = &routeNode{children: make(map[string]*routeNode)}
+				node.paramName = seg.text
+			} else if node.paramName != seg.text {
+				return fmt.Errorf("p9: pattern %q conflicts with an existing :%s pattern at the same position", pattern, node.paramName)
+			}
+			node = node.paramChild
+		case segWildcard:
+			if node.wildcardHandler != nil && node.wildcardName != seg.text {
+				return fmt.Errorf("p9: pattern %q conflicts with an existing *%s pattern at the same position", pattern, node.wildcardName)
+			}
+			node.wildcardName = seg.text
+			node.wildcardHandler = h
+			node.wildcardPattern = pattern
+			_ = i
+			return nil
+		}
+	}
+	node.handler = h
+	node.pattern = pattern
+	return nil
+}
+
+// remove clears the handler segs points at, if one is registered, and
+// reports whether it found one. It leaves now-empty intermediate nodes in
+// place; Mux's route counts are small and long-lived enough that pruning
+// them isn't worth the bookkeeping.
+func (t *routeTree) remove(segs []segment) bool {
+	node := t.root
+	for _, seg := range segs {
+		switch seg.kind {
+		case segLiteral:
+			child, ok := node.children[seg.text]
+			if !ok {
+				return false
+			}
+			node = child
+		case segParam:
+			if node.paramChild == nil {
+				return false
+			}
+			node = node.paramChild
+		case segWildcard:
+			if node.wildcardHandler == nil {
+				return false
+			}
+			node.wildcardHandler = nil
+			node.wildcardName = ""
+			node.wildcardPattern = ""
+			return true
+		}
+	}
+	if node.handler == nil {
+		return false
+	}
+	node.handler = nil
+	node.pattern = ""
+	return true
+}
+
+// lookup matches path against the tree, preferring a literal child over a
+// :param continuation over a *wildcard match at every position -- the
+// precedence that lets Register's conflict detection treat "literal
+// alongside :param/*wildcard at the same spot" as unambiguous, since the
+// deterministic tie-break decides it.
+func (t *routeTree) lookup(path string) (Handler, map[string]string, string, bool) {
+	segs := splitSegments(path)
+	node := t.root
+	var params map[string]string
+	for i, s := range segs {
+		if child, ok := node.children[s]; ok {
+			node = child
+			continue
+		}
+		if node.paramChild != nil {
+			if params == nil {
+				params = make(map[string]string, 1)
+			}
+			params[node.paramName] = s
+			node = node.paramChild
+			continue
+		}
+		if node.wildcardHandler != nil {
+			if params == nil {
+				params = make(map[string]string, 1)
+			}
+			params[node.wildcardName] = strings.Join(segs[i:], "/")
+			return node.wildcardHandler, params, node.wildcardPattern, true
+		}
+		return nil, nil, "", false
+	}
+	if node.handler != nil {
+		return node.handler, params, node.pattern, true
+	}
+	if node.wildcardHandler != nil {
+		if params == nil {
+			params = make(map[string]string, 1)
+		}
+		params[node.wildcardName] = ""
+		return node.wildcardHandler, params, node.wildcardPattern, true
+	}
+	return nil, nil, "", false
 }
@@ -1,20 +1,181 @@
 // CLASSIFICATION: COMMUNITY
-// Filename: mux_test.go v0.1
-// Date Modified: 2025-07-07
+// Filename: mux_test.go v0.3
+// Date Modified: 2026-07-26
 // Author: Lukas Bower
 package p9
 
-import "testing"
+import (
+	"context"
+	"testing"
+	"time"
+)
 
 type dummy struct{}
 
-func (d dummy) Handle(path string, data []byte) ([]byte, error) { return data, nil }
+func (d dummy) Handle(ctx context.Context, req Request) (Response, error) {
+	return Response{Data: req.Data}, nil
+}
+
+func TestMuxDo(t *testing.T) {
+	m := NewMux()
+	m.Register("/srv/test", AdaptSimpleHandler(dummy{}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.Serve(ctx)
+
+	resp, err := m.Do(context.Background(), "/srv/test", []byte("hi"))
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if string(resp.Data) != "hi" {
+		t.Fatalf("expected echo, got %q", resp.Data)
+	}
+}
+
+func TestMuxLiteralBeatsWildcard(t *testing.T) {
+	m := NewMux()
+	m.Register("/srv/*rest", HandlerFunc(func(ctx Context, data []byte) ([]byte, error) {
+		return []byte("wild:" + ctx.Param("rest")), nil
+	}))
+	m.Register("/srv/test", HandlerFunc(func(ctx Context, data []byte) ([]byte, error) {
+		return []byte("literal:" + ctx.Path()), nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.Serve(ctx)
+
+	resp, err := m.Do(context.Background(), "/srv/test", nil)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if string(resp.Data) != "literal:/srv/test" {
+		t.Fatalf("expected literal match to win over wildcard, got %q", resp.Data)
+	}
+
+	resp, err = m.Do(context.Background(), "/srv/other/thing", nil)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if string(resp.Data) != "wild:other/thing" {
+		t.Fatalf("expected wildcard fallback, got %q", resp.Data)
+	}
+}
+
+func TestMuxParamCapture(t *testing.T) {
+	m := NewMux()
+	m.Register("/srv/:vol/files/*path", HandlerFunc(func(ctx Context, data []byte) ([]byte, error) {
+		return []byte(ctx.Param("vol") + ":" + ctx.Param("path")), nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.Serve(ctx)
+
+	resp, err := m.Do(context.Background(), "/srv/data/files/a/b.txt", nil)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if string(resp.Data) != "data:a/b.txt" {
+		t.Fatalf("unexpected captures: %q", resp.Data)
+	}
+}
+
+func TestMuxRegisterRejectsAmbiguousParamNames(t *testing.T) {
+	m := NewMux()
+	if err := m.Register("/srv/:vol", AdaptSimpleHandler(dummy{})); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := m.Register("/srv/:disk", AdaptSimpleHandler(dummy{})); err == nil {
+		t.Fatal("expected conflicting :param name to be rejected")
+	}
+}
+
+func TestMuxDoNoRoute(t *testing.T) {
+	m := NewMux()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.Serve(ctx)
+
+	if _, err := m.Do(context.Background(), "/srv/missing", nil); err == nil {
+		t.Fatal("expected error for unregistered path")
+	}
+}
+
+func TestMuxWatch(t *testing.T) {
+	m := NewMux()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, stop := m.Watch(ctx, "/srv")
+	defer stop()
+
+	m.Register("/srv/test", AdaptSimpleHandler(dummy{}))
+	select {
+	case ev := <-events:
+		if ev.Type != EventAdded || ev.Path != "/srv/test" {
+			t.Fatalf("unexpected event: %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for watch event")
+	}
+
+	m.Deregister("/srv/test")
+	select {
+	case ev := <-events:
+		if ev.Type != EventRemoved || ev.Path != "/srv/test" {
+			t.Fatalf("unexpected event: %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for watch event")
+	}
+}
+
+func TestMuxDoTagsAreUniquePerCall(t *testing.T) {
+	m := NewMux()
+	m.Register("/srv/test", AdaptSimpleHandler(dummy{}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.Serve(ctx)
+
+	resp1, err := m.Do(context.Background(), "/srv/test", []byte("a"))
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp2, err := m.Do(context.Background(), "/srv/test", []byte("b"))
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if resp1.Tag == 0 || resp2.Tag == 0 {
+		t.Fatalf("expected non-zero tags, got %d and %d", resp1.Tag, resp2.Tag)
+	}
+	if resp1.Tag == resp2.Tag {
+		t.Fatalf("expected distinct tags per call, both got %d", resp1.Tag)
+	}
+}
+
+func TestMuxMiddleware(t *testing.T) {
+	m := NewMux()
+	m.Register("/srv/test", AdaptSimpleHandler(dummy{}))
+
+	var called bool
+	m.Use(func(next Handler) Handler {
+		return HandlerFunc(func(ctx Context, data []byte) ([]byte, error) {
+			called = true
+			return next.Handle(ctx, data)
+		})
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.Serve(ctx)
 
-func TestMuxRegister(t *testing.T) {
-    m := NewMux()
-    m.Register("/srv/test", dummy{})
-    out := m.Handle("/srv/test/foo", []byte("hi"))
-    if string(out) != "hi" {
-        t.Fatalf("expected echo")
-    }
+	if _, err := m.Do(context.Background(), "/srv/test", []byte("hi")); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if !called {
+		t.Fatal("expected middleware to run")
+	}
 }
@@ -1,7 +1,7 @@
 // CLASSIFICATION: COMMUNITY
-// Filename: context.go v0.2
+// Filename: context.go v0.3
 // Author: Lukas Bower
-// Date Modified: 2025-07-15
+// Date Modified: 2026-07-25
 
 package agentsdk
 
@@ -9,10 +9,26 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"os"
+	"strings"
 	"sync"
-	"time"
+
+	"cohesix/internal/agent_sdk/store"
+	"cohesix/internal/logging"
+)
+
+const (
+	envStateBackend   = "COHESIX_STATE_BACKEND"
+	envEtcdEndpoints  = "COHESIX_ETCD_ENDPOINTS"
+	envRole           = "COHESIX_ROLE"
+	envAgentID        = "COHESIX_AGENT_ID"
+	defaultFSStoreDir = "/srv"
+
+	keyRole     = "agent_meta/role.txt"
+	keyUptime   = "agent_meta/uptime.txt"
+	keyTraceID  = "agent_meta/trace_id.txt"
+	keyLastGoal = "agent_meta/last_goal.json"
+	keyWorld    = "world_state/world.json"
 )
 
 type AgentContext struct {
@@ -22,32 +38,32 @@ type AgentContext struct {
 	LastGoal      map[string]any
 	WorldSnapshot map[string]any
 	TraceID       string
-}
 
-func readFile(path string) []byte {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil
-	}
-	return data
-}
-
-func logEvent(role, event, traceID string) {
-	rec := map[string]any{
-		"ts":       time.Now().Format(time.RFC3339Nano),
-		"role":     role,
-		"event":    event,
-		"trace_id": traceID,
-	}
-	b, _ := json.Marshal(rec)
-	log.Print(string(b))
+	store      store.Store
+	lastGoalRv int64
+	log        logging.Logger
 }
 
-// UpdateLastGoal safely updates the agent's last goal.
-func (a *AgentContext) UpdateLastGoal(goal map[string]any) {
+// UpdateLastGoal safely updates the agent's last goal and persists it to
+// the backing Store using optimistic concurrency, so that competing
+// controllers writing the same key cannot silently clobber each other.
+func (a *AgentContext) UpdateLastGoal(goal map[string]any) error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
+
+	data, err := json.Marshal(goal)
+	if err != nil {
+		return fmt.Errorf("marshal last goal: %w", err)
+	}
+	if a.store != nil {
+		rev, err := a.store.CompareAndSwap(context.Background(), keyLastGoal, a.lastGoalRv, data)
+		if err != nil {
+			return fmt.Errorf("persist last goal: %w", err)
+		}
+		a.lastGoalRv = rev
+	}
 	a.LastGoal = goal
+	return nil
 }
 
 // LastGoalCopy returns a snapshot of the last goal.
@@ -61,7 +77,9 @@ func (a *AgentContext) LastGoalCopy() map[string]any {
 	return cp
 }
 
-// WorldSnapshotCopy returns a snapshot of the world state.
+// WorldSnapshotCopy returns a snapshot of the world state. Once New has
+// subscribed to the world key, this reflects the latest Store-pushed
+// update rather than a value re-read from disk on every call.
 func (a *AgentContext) WorldSnapshotCopy() map[string]any {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -72,32 +90,103 @@ func (a *AgentContext) WorldSnapshotCopy() map[string]any {
 	return cp
 }
 
+// openStore selects a Store implementation based on COHESIX_STATE_BACKEND
+// ("fs" or "etcd", default "fs"). An etcd dial failure falls back to the
+// filesystem backend so a single unreachable peer doesn't prevent an agent
+// from booting.
+func openStore(log logging.Logger, role, agentID string) store.Store {
+	backend := strings.ToLower(strings.TrimSpace(os.Getenv(envStateBackend)))
+	if backend == "etcd" {
+		endpoints := strings.FieldsFunc(os.Getenv(envEtcdEndpoints), func(r rune) bool { return r == ',' })
+		if len(endpoints) > 0 {
+			st, err := store.NewEtcdStore(context.Background(), role+"/"+agentID, store.EtcdOptions{Endpoints: endpoints})
+			if err == nil {
+				return st
+			}
+			log.Warn("etcd store unavailable, falling back to filesystem", "error", err)
+		} else {
+			log.Warn("etcd endpoints unset, falling back to filesystem", "env", envEtcdEndpoints)
+		}
+	}
+	st, err := store.NewFSStore(defaultFSStoreDir)
+	if err != nil {
+		log.Error("filesystem store unavailable", "error", err)
+		return nil
+	}
+	return st
+}
+
 func New() *AgentContext {
+	role := os.Getenv(envRole)
+	if role == "" {
+		role = "Unknown"
+	}
+	agentID := os.Getenv(envAgentID)
+	if agentID == "" {
+		agentID = "unknown-agent"
+	}
+
+	log := logging.New("agentsdk").With(logging.FieldRole, role, logging.FieldWorkerID, agentID)
+
 	ctx := &AgentContext{
-		Role:          "Unknown",
+		Role:          role,
 		Uptime:        "0",
 		LastGoal:      map[string]any{},
 		WorldSnapshot: map[string]any{},
+		store:         openStore(log, role, agentID),
+		log:           log,
 	}
-	if b := readFile("/srv/agent_meta/role.txt"); b != nil {
-		ctx.Role = string(b)
-	}
-	if b := readFile("/srv/agent_meta/uptime.txt"); b != nil {
-		ctx.Uptime = string(b)
-	}
-	if b := readFile("/srv/agent_meta/trace_id.txt"); b != nil {
-		ctx.TraceID = string(b)
-	}
-	if b := readFile("/srv/agent_meta/last_goal.json"); b != nil {
-		json.Unmarshal(b, &ctx.LastGoal)
-	}
-	if b := readFile("/srv/world_state/world.json"); b != nil {
-		json.Unmarshal(b, &ctx.WorldSnapshot)
+
+	if ctx.store != nil {
+		if v, err := ctx.store.Get(context.Background(), keyRole); err == nil {
+			ctx.Role = string(v.Data)
+		}
+		if v, err := ctx.store.Get(context.Background(), keyUptime); err == nil {
+			ctx.Uptime = string(v.Data)
+		}
+		if v, err := ctx.store.Get(context.Background(), keyTraceID); err == nil {
+			ctx.TraceID = string(v.Data)
+		}
+		if v, err := ctx.store.Get(context.Background(), keyLastGoal); err == nil {
+			json.Unmarshal(v.Data, &ctx.LastGoal)
+			ctx.lastGoalRv = v.Revision
+		}
+		if v, err := ctx.store.Get(context.Background(), keyWorld); err == nil {
+			json.Unmarshal(v.Data, &ctx.WorldSnapshot)
+		}
+		ctx.watchWorld()
 	}
-	logEvent(ctx.Role, "agent_init", ctx.TraceID)
+
+	ctx.log = ctx.log.With(logging.FieldTraceID, ctx.TraceID)
+	ctx.log.Info("agent_init")
 	return ctx
 }
 
+// watchWorld subscribes to the world snapshot key so WorldSnapshotCopy
+// reflects push updates from the Store (e.g. the physics worker publishing
+// a new world.json) instead of requiring callers to poll.
+func (a *AgentContext) watchWorld() {
+	events, err := a.store.Watch(context.Background(), keyWorld)
+	if err != nil {
+		a.log.Warn("watch world state", "error", err)
+		return
+	}
+	go func() {
+		for ev := range events {
+			if ev.Deleted {
+				continue
+			}
+			var world map[string]any
+			if err := json.Unmarshal(ev.Value, &world); err != nil {
+				continue
+			}
+			a.mu.Lock()
+			a.WorldSnapshot = world
+			a.mu.Unlock()
+		}
+	}()
+}
+
 type traceIDKey struct{}
 
 // Run executes fn within the provided context and attaches the trace ID.
@@ -107,8 +196,7 @@ func (a *AgentContext) Run(ctx context.Context, fn func(context.Context) error)
 		ctx = context.Background()
 	}
 	ctx = context.WithValue(ctx, traceIDKey{}, a.TraceID)
-	logEvent(a.Role, "agent_start", a.TraceID)
-	log.Printf("[role=%s trace=%s] run begin", a.Role, a.TraceID)
+	a.log.Info("run begin")
 
 	done := make(chan struct{})
 	go func() {
@@ -125,21 +213,25 @@ func (a *AgentContext) Run(ctx context.Context, fn func(context.Context) error)
 
 	select {
 	case <-ctx.Done():
-		log.Printf("[role=%s trace=%s] context done: %v", a.Role, a.TraceID, ctx.Err())
+		a.log.Warn("context done", "error", ctx.Err())
 		return ctx.Err()
 	case <-done:
-		log.Printf("[role=%s trace=%s] run end", a.Role, a.TraceID)
+		a.log.Info("run end")
 		return err
 	}
 }
 
-// Shutdown emits shutdown event for graceful teardown.
+// Shutdown emits shutdown event for graceful teardown and releases the
+// backing Store (e.g. revoking the etcd lease so liveness keys expire
+// immediately rather than waiting out the lease TTL).
 func (a *AgentContext) Shutdown(ctx context.Context) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 	ctx = context.WithValue(ctx, traceIDKey{}, a.TraceID)
-	logEvent(a.Role, "agent_shutdown", a.TraceID)
-	log.Printf("[role=%s trace=%s] shutdown", a.Role, a.TraceID)
+	a.log.Info("agent_shutdown")
+	if a.store != nil {
+		return a.store.Close()
+	}
 	return nil
 }
@@ -0,0 +1,176 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: etcd.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-25
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package store
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// defaultLeaseTTL is how long an agent's liveness lease is granted for.
+// NewEtcdStore keeps it alive with KeepAlive until Close is called; a
+// crashed agent's keys expire on their own after this window.
+const defaultLeaseTTL = 30 * time.Second
+
+// EtcdOptions configures an etcd-backed Store.
+type EtcdOptions struct {
+	Endpoints   []string
+	DialTimeout time.Duration
+	// LeaseTTL overrides defaultLeaseTTL; primarily for tests.
+	LeaseTTL time.Duration
+}
+
+// EtcdStore implements Store against an etcd v3 cluster. All keys are
+// written under a single namespace prefix (e.g. /cohesix/<role>/<agent_id>)
+// and tied to one lease, so an agent's entire keyspace disappears if it
+// stops renewing the lease.
+type EtcdStore struct {
+	client    *clientv3.Client
+	namespace string
+	leaseID   clientv3.LeaseID
+	cancelKA  context.CancelFunc
+}
+
+// NewEtcdStore dials etcd and grants a lease for namespace, which is used
+// as a key prefix (joined with "/") for every subsequent operation. The
+// lease is kept alive for the lifetime of the returned Store; callers must
+// call Close to release it promptly on shutdown.
+func NewEtcdStore(ctx context.Context, namespace string, opts EtcdOptions) (*EtcdStore, error) {
+	if len(opts.Endpoints) == 0 {
+		return nil, fmt.Errorf("etcd store: at least one endpoint is required")
+	}
+	dialTimeout := opts.DialTimeout
+	if dialTimeout <= 0 {
+		dialTimeout = 5 * time.Second
+	}
+	cli, err := clientv3.New(clientv3.Config{
+		Endpoints:   opts.Endpoints,
+		DialTimeout: dialTimeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("etcd store: dial: %w", err)
+	}
+
+	ttl := opts.LeaseTTL
+	if ttl <= 0 {
+		ttl = defaultLeaseTTL
+	}
+	lease, err := cli.Grant(ctx, int64(ttl.Seconds()))
+	if err != nil {
+		cli.Close()
+		return nil, fmt.Errorf("etcd store: grant lease: %w", err)
+	}
+
+	kaCtx, cancel := context.WithCancel(context.Background())
+	keepAlive, err := cli.KeepAlive(kaCtx, lease.ID)
+	if err != nil {
+		cancel()
+		cli.Close()
+		return nil, fmt.Errorf("etcd store: keep alive: %w", err)
+	}
+	go func() {
+		for range keepAlive {
+			// Drain keepalive responses; nothing to do on success.
+		}
+	}()
+
+	return &EtcdStore{
+		client:    cli,
+		namespace: strings.Trim(namespace, "/"),
+		leaseID:   lease.ID,
+		cancelKA:  cancel,
+	}, nil
+}
+
+func (s *EtcdStore) fullKey(key string) string {
+	return "/" + path.Join("cohesix", s.namespace, key)
+}
+
+// Get implements Store.
+func (s *EtcdStore) Get(ctx context.Context, key string) (Value, error) {
+	resp, err := s.client.Get(ctx, s.fullKey(key))
+	if err != nil {
+		return Value{}, err
+	}
+	if len(resp.Kvs) == 0 {
+		return Value{}, ErrNotFound
+	}
+	kv := resp.Kvs[0]
+	return Value{Data: kv.Value, Revision: kv.ModRevision}, nil
+}
+
+// Put implements Store.
+func (s *EtcdStore) Put(ctx context.Context, key string, value []byte) (int64, error) {
+	resp, err := s.client.Put(ctx, s.fullKey(key), string(value), clientv3.WithLease(s.leaseID))
+	if err != nil {
+		return 0, err
+	}
+	return resp.Header.Revision, nil
+}
+
+// CompareAndSwap implements Store using an etcd transaction so concurrent
+// controllers cannot clobber each other's writes.
+func (s *EtcdStore) CompareAndSwap(ctx context.Context, key string, expectRevision int64, value []byte) (int64, error) {
+	full := s.fullKey(key)
+	txn := s.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(full), "=", expectRevision)).
+		Then(clientv3.OpPut(full, string(value), clientv3.WithLease(s.leaseID))).
+		Else(clientv3.OpGet(full))
+	resp, err := txn.Commit()
+	if err != nil {
+		return 0, err
+	}
+	if !resp.Succeeded {
+		return 0, ErrCompareFailed
+	}
+	return resp.Header.Revision, nil
+}
+
+// Watch implements Store, pushing every subsequent revision of key to the
+// returned channel until ctx is cancelled.
+func (s *EtcdStore) Watch(ctx context.Context, key string) (<-chan Event, error) {
+	full := s.fullKey(key)
+	wch := s.client.Watch(ctx, full)
+	out := make(chan Event, 8)
+	go func() {
+		defer close(out)
+		for resp := range wch {
+			for _, ev := range resp.Events {
+				e := Event{Key: key, Revision: ev.Kv.ModRevision}
+				if ev.Type == clientv3.EventTypeDelete {
+					e.Deleted = true
+				} else {
+					e.Value = ev.Kv.Value
+				}
+				select {
+				case out <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+// Close revokes the lease (dropping every key written under it) and closes
+// the underlying client connection.
+func (s *EtcdStore) Close() error {
+	s.cancelKA()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := s.client.Revoke(ctx, s.leaseID)
+	if cerr := s.client.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,167 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: fs.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-25
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package store
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FSStore implements Store on top of the historical loose-file layout under
+// a root directory. Revisions are derived from the file's modification time
+// in nanoseconds, which is precise enough to detect the single-writer races
+// this backend is meant to support; multi-node deployments should use the
+// etcd backend instead.
+type FSStore struct {
+	root string
+
+	mu      sync.Mutex
+	waiters map[string][]chan Event
+}
+
+// NewFSStore returns a Store rooted at dir. dir is created if it does not
+// already exist.
+func NewFSStore(dir string) (*FSStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("fs store: mkdir %s: %w", dir, err)
+	}
+	return &FSStore{root: dir, waiters: make(map[string][]chan Event)}, nil
+}
+
+func (s *FSStore) path(key string) string {
+	return filepath.Join(s.root, filepath.FromSlash(key))
+}
+
+// Get implements Store.
+func (s *FSStore) Get(_ context.Context, key string) (Value, error) {
+	data, err := os.ReadFile(s.path(key))
+	if os.IsNotExist(err) {
+		return Value{}, ErrNotFound
+	}
+	if err != nil {
+		return Value{}, err
+	}
+	info, err := os.Stat(s.path(key))
+	if err != nil {
+		return Value{}, err
+	}
+	return Value{Data: data, Revision: info.ModTime().UnixNano()}, nil
+}
+
+// Put implements Store.
+func (s *FSStore) Put(_ context.Context, key string, value []byte) (int64, error) {
+	return s.write(key, value)
+}
+
+// CompareAndSwap implements Store.
+func (s *FSStore) CompareAndSwap(ctx context.Context, key string, expectRevision int64, value []byte) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := s.Get(ctx, key)
+	switch {
+	case err == ErrNotFound:
+		if expectRevision != 0 {
+			return 0, ErrCompareFailed
+		}
+	case err != nil:
+		return 0, err
+	default:
+		if current.Revision != expectRevision {
+			return 0, ErrCompareFailed
+		}
+	}
+	return s.write(key, value)
+}
+
+func (s *FSStore) write(key string, value []byte) (int64, error) {
+	p := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return 0, err
+	}
+	if err := os.WriteFile(p, value, 0o644); err != nil {
+		return 0, err
+	}
+	info, err := os.Stat(p)
+	if err != nil {
+		return 0, err
+	}
+	rev := info.ModTime().UnixNano()
+	s.notify(key, Event{Key: key, Value: value, Revision: rev})
+	return rev, nil
+}
+
+func (s *FSStore) notify(key string, ev Event) {
+	for _, ch := range s.waiters[key] {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// removeWaiter drops ch from key's waiter slice without disturbing any other
+// in-flight Watch calls on the same key.
+func (s *FSStore) removeWaiter(key string, ch chan Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	waiters := s.waiters[key]
+	for i, w := range waiters {
+		if w == ch {
+			s.waiters[key] = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(s.waiters[key]) == 0 {
+		delete(s.waiters, key)
+	}
+}
+
+// Watch polls the file for key every pollInterval and emits an Event
+// whenever its content changes. This trades efficiency for the simplicity
+// appropriate to a single-node dev backend.
+func (s *FSStore) Watch(ctx context.Context, key string) (<-chan Event, error) {
+	ch := make(chan Event, 1)
+	s.mu.Lock()
+	s.waiters[key] = append(s.waiters[key], ch)
+	s.mu.Unlock()
+
+	go func() {
+		const pollInterval = 500 * time.Millisecond
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		var lastRev int64 = -1
+		for {
+			select {
+			case <-ctx.Done():
+				s.removeWaiter(key, ch)
+				close(ch)
+				return
+			case <-ticker.C:
+				v, err := s.Get(ctx, key)
+				if err == ErrNotFound || err != nil {
+					continue
+				}
+				if v.Revision != lastRev {
+					lastRev = v.Revision
+					select {
+					case ch <- Event{Key: key, Value: v.Data, Revision: v.Revision}:
+					default:
+					}
+				}
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// Close is a no-op for FSStore.
+func (s *FSStore) Close() error { return nil }
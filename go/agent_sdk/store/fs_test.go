@@ -0,0 +1,136 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: fs_test.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package store
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFSStoreCompareAndSwapRejectsStaleRevision(t *testing.T) {
+	s, err := NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("new fs store: %v", err)
+	}
+
+	rev, err := s.Put(context.Background(), "agents/a", []byte("v1"))
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	if _, err := s.CompareAndSwap(context.Background(), "agents/a", rev, []byte("v2")); err != nil {
+		t.Fatalf("expected CAS against the current revision to succeed, got %v", err)
+	}
+
+	// rev is now stale: the CAS above already advanced the key.
+	if _, err := s.CompareAndSwap(context.Background(), "agents/a", rev, []byte("v3")); err != ErrCompareFailed {
+		t.Fatalf("expected ErrCompareFailed for a stale revision, got %v", err)
+	}
+}
+
+func TestFSStoreCompareAndSwapRequiresZeroRevisionForNewKey(t *testing.T) {
+	s, err := NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("new fs store: %v", err)
+	}
+
+	if _, err := s.CompareAndSwap(context.Background(), "agents/new", 1, []byte("v1")); err != ErrCompareFailed {
+		t.Fatalf("expected ErrCompareFailed for a nonexistent key with nonzero expectRevision, got %v", err)
+	}
+	if _, err := s.CompareAndSwap(context.Background(), "agents/new", 0, []byte("v1")); err != nil {
+		t.Fatalf("expected CAS with expectRevision 0 to create the key, got %v", err)
+	}
+}
+
+// TestFSStoreCompareAndSwapUnderContentionOnlyOneWinner races N goroutines,
+// each trying to CAS the same key from the same starting revision. Exactly
+// one should succeed; every other caller must observe ErrCompareFailed
+// rather than clobbering the winner's write.
+func TestFSStoreCompareAndSwapUnderContentionOnlyOneWinner(t *testing.T) {
+	s, err := NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("new fs store: %v", err)
+	}
+
+	rev, err := s.Put(context.Background(), "agents/a", []byte("v0"))
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	const n = 8
+	var wg sync.WaitGroup
+	successes := make(chan int64, n)
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		i := i
+		go func() {
+			defer wg.Done()
+			if newRev, err := s.CompareAndSwap(context.Background(), "agents/a", rev, []byte("writer")); err == nil {
+				successes <- newRev
+			} else if err != ErrCompareFailed {
+				t.Errorf("unexpected error from goroutine %d: %v", i, err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(successes)
+
+	var count int
+	for range successes {
+		count++
+	}
+	if count != 1 {
+		t.Fatalf("expected exactly 1 successful CAS under contention, got %d", count)
+	}
+}
+
+func TestFSStoreWatchCancelOnlyRemovesItsOwnChannel(t *testing.T) {
+	s, err := NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("new fs store: %v", err)
+	}
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	ch1, err := s.Watch(ctx1, "agents/a")
+	if err != nil {
+		t.Fatalf("watch 1: %v", err)
+	}
+
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	defer cancel2()
+	ch2, err := s.Watch(ctx2, "agents/a")
+	if err != nil {
+		t.Fatalf("watch 2: %v", err)
+	}
+
+	// Cancel the first watch and let its goroutine clean up.
+	cancel1()
+	select {
+	case _, ok := <-ch1:
+		if ok {
+			t.Fatal("expected ch1 to be closed after cancellation")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for ch1 to close")
+	}
+
+	// The second watch on the same key must still be notified: the first
+	// watch's cleanup must not have deleted the whole s.waiters[key] slice.
+	if _, err := s.Put(context.Background(), "agents/a", []byte("v1")); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	select {
+	case ev := <-ch2:
+		if ev.Key != "agents/a" || string(ev.Value) != "v1" {
+			t.Fatalf("unexpected event: %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for ch2 to receive the write notification")
+	}
+}
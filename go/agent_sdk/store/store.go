@@ -0,0 +1,65 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: store.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-25
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+// Package store provides a versioned key/value abstraction over the state
+// that AgentContext and the physics worker used to read and write as loose
+// files under /srv. A Store lets that state live either on the local
+// filesystem (the historical layout) or in a shared etcd cluster, selected
+// at runtime via COHESIX_STATE_BACKEND.
+package store
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned by Get when a key has no value.
+var ErrNotFound = errors.New("store: key not found")
+
+// ErrCompareFailed is returned by CompareAndSwap when the key's current
+// revision does not match the expected revision.
+var ErrCompareFailed = errors.New("store: compare failed")
+
+// Value is a versioned blob returned by Get and delivered through Watch.
+type Value struct {
+	Data     []byte
+	Revision int64
+}
+
+// Event describes a single change observed by Watch.
+type Event struct {
+	Key      string
+	Value    []byte
+	Revision int64
+	Deleted  bool
+}
+
+// Store is a namespaced, versioned key/value store. Keys are always scoped
+// to the namespace a Store was opened with (see fs.go and etcd.go), so
+// callers pass paths relative to that namespace, e.g. "role" or
+// "world.json".
+type Store interface {
+	// Get returns the current value and revision for key.
+	Get(ctx context.Context, key string) (Value, error)
+
+	// Put unconditionally writes value under key and returns the new
+	// revision.
+	Put(ctx context.Context, key string, value []byte) (int64, error)
+
+	// CompareAndSwap writes value under key only if the key's current
+	// revision equals expectRevision. Pass expectRevision 0 to require the
+	// key does not yet exist. Returns ErrCompareFailed on mismatch.
+	CompareAndSwap(ctx context.Context, key string, expectRevision int64, value []byte) (int64, error)
+
+	// Watch streams Events for key until ctx is cancelled or the returned
+	// channel is drained and closed. The channel is closed when ctx is
+	// done.
+	Watch(ctx context.Context, key string) (<-chan Event, error)
+
+	// Close releases any resources (connections, leases) held by the
+	// Store.
+	Close() error
+}
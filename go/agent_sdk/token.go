@@ -0,0 +1,50 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: token.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package agentsdk
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"cohesix/internal/jwtauth"
+)
+
+const (
+	envJWTPublicKeyFile  = "COHESIX_JWT_PUBLIC_KEY_FILE"
+	envJWTPrivateKeyFile = "COHESIX_JWT_PRIVATE_KEY_FILE"
+	envJWTIssuer         = "COHESIX_JWT_ISSUER"
+	envJWTAudience       = "COHESIX_JWT_AUDIENCE"
+)
+
+// MintPeerToken signs a short-lived "Authorization: Bearer" token this
+// agent can present to another cluster orchestrator's HTTP control plane
+// -- the agentsdk-side counterpart of the RS256 verification
+// jwtauth.JWTVerifier performs there. It requires both
+// COHESIX_JWT_PUBLIC_KEY_FILE and COHESIX_JWT_PRIVATE_KEY_FILE to be set;
+// an orchestrator running verify-only (public key only) cannot mint
+// tokens, so it has no use for this helper.
+func MintPeerToken(agentID string, roles []string, ttl time.Duration) (string, error) {
+	pubFile := os.Getenv(envJWTPublicKeyFile)
+	privFile := os.Getenv(envJWTPrivateKeyFile)
+	if pubFile == "" || privFile == "" {
+		return "", fmt.Errorf("%s and %s must both be set to mint peer tokens", envJWTPublicKeyFile, envJWTPrivateKeyFile)
+	}
+	pubPEM, err := os.ReadFile(pubFile)
+	if err != nil {
+		return "", fmt.Errorf("read jwt public key: %w", err)
+	}
+	privPEM, err := os.ReadFile(privFile)
+	if err != nil {
+		return "", fmt.Errorf("read jwt private key: %w", err)
+	}
+	verifier, err := jwtauth.NewJWTVerifier(pubPEM, privPEM, os.Getenv(envJWTIssuer), os.Getenv(envJWTAudience))
+	if err != nil {
+		return "", fmt.Errorf("init jwt verifier: %w", err)
+	}
+	return verifier.MintToken(agentID, roles, ttl)
+}
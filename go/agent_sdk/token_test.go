@@ -0,0 +1,69 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: token_test.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+
+package agentsdk
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cohesix/internal/jwtauth"
+)
+
+func TestMintPeerTokenVerifiesAgainstAPIVerifier(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+	privPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	dir := t.TempDir()
+	pubFile := filepath.Join(dir, "pub.pem")
+	privFile := filepath.Join(dir, "priv.pem")
+	if err := os.WriteFile(pubFile, pubPEM, 0o600); err != nil {
+		t.Fatalf("write public key: %v", err)
+	}
+	if err := os.WriteFile(privFile, privPEM, 0o600); err != nil {
+		t.Fatalf("write private key: %v", err)
+	}
+	t.Setenv(envJWTPublicKeyFile, pubFile)
+	t.Setenv(envJWTPrivateKeyFile, privFile)
+
+	token, err := MintPeerToken("worker-a", []string{"DroneWorker"}, time.Minute)
+	if err != nil {
+		t.Fatalf("mint peer token: %v", err)
+	}
+
+	verifier, err := jwtauth.NewJWTVerifier(pubPEM, nil, "", "")
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+	claims, err := verifier.VerifyToken(token)
+	if err != nil {
+		t.Fatalf("verify token: %v", err)
+	}
+	if claims.Subject != "worker-a" {
+		t.Fatalf("unexpected subject: %s", claims.Subject)
+	}
+}
+
+func TestMintPeerTokenRequiresBothKeyFiles(t *testing.T) {
+	t.Setenv(envJWTPublicKeyFile, "")
+	t.Setenv(envJWTPrivateKeyFile, "")
+	if _, err := MintPeerToken("worker-a", []string{"DroneWorker"}, time.Minute); err == nil {
+		t.Fatal("expected an error when no key files are configured")
+	}
+}
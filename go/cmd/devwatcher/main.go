@@ -1,60 +1,99 @@
 // CLASSIFICATION: COMMUNITY
-// Filename: main.go v0.1
+// Filename: main.go v0.3
 // Author: Lukas Bower
-// Date Modified: 2027-01-31
+// Date Modified: 2026-07-26
 // License: SPDX-License-Identifier: MIT OR Apache-2.0
 
 package main
 
 import (
-    "bufio"
-    "fmt"
-    "os"
-    "strings"
-    "sync"
-    "time"
-
-    "github.com/fsnotify/fsnotify"
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+
+	p9 "cohesix/internal/9p"
 )
 
+// ctlPrefix is the p9.Mux prefix new watch entries are registered under.
+// Registering a path notifies Watch subscribers below instead of the old
+// loop re-reading and re-diffing /dev/watch/ctl every second.
+const ctlPrefix = "/dev/watch/ctl/"
+
+// noopHandler services Do() for a registered path; devwatcher only cares
+// about the Register/Deregister events Watch observes, not Do itself.
+type noopHandler struct{}
+
+func (noopHandler) Handle(ctx p9.Context, data []byte) ([]byte, error) {
+	return nil, nil
+}
+
 func main() {
-    os.MkdirAll("/dev/watch", 0755)
-    os.WriteFile("/dev/watch/ctl", []byte{}, 0644)
-    f, _ := os.OpenFile("/dev/watch/events", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
-    f.Close()
-
-    watcher, _ := fsnotify.NewWatcher()
-    var mu sync.Mutex
-    watched := make(map[string]bool)
-
-    go func() {
-        out, _ := os.OpenFile("/dev/watch/events", os.O_WRONLY|os.O_APPEND, 0644)
-        defer out.Close()
-        for {
-            select {
-            case ev := <-watcher.Events:
-                fmt.Fprintf(out, "%s %s\n", ev.Name, ev.Op.String())
-            case err := <-watcher.Errors:
-                fmt.Fprintf(out, "error %v\n", err)
-            }
-        }
-    }()
-
-    for {
-        data, _ := os.ReadFile("/dev/watch/ctl")
-        scanner := bufio.NewScanner(strings.NewReader(string(data)))
-        for scanner.Scan() {
-            p := strings.TrimSpace(scanner.Text())
-            if p == "" {
-                continue
-            }
-            mu.Lock()
-            if !watched[p] {
-                watcher.Add(p)
-                watched[p] = true
-            }
-            mu.Unlock()
-        }
-        time.Sleep(1 * time.Second)
-    }
+	os.MkdirAll("/dev/watch", 0755)
+	os.WriteFile("/dev/watch/ctl", []byte{}, 0644)
+	f, _ := os.OpenFile("/dev/watch/events", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	f.Close()
+
+	fsw, _ := fsnotify.NewWatcher()
+	ctlWatcher, _ := fsnotify.NewWatcher()
+	ctlWatcher.Add("/dev/watch/ctl")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mux := p9.NewMux()
+	go mux.Serve(ctx)
+
+	events, stopWatch := mux.Watch(ctx, ctlPrefix)
+	defer stopWatch()
+
+	go func() {
+		out, _ := os.OpenFile("/dev/watch/events", os.O_WRONLY|os.O_APPEND, 0644)
+		defer out.Close()
+		for {
+			select {
+			case ev := <-fsw.Events:
+				fmt.Fprintf(out, "%s %s\n", ev.Name, ev.Op.String())
+			case err := <-fsw.Errors:
+				fmt.Fprintf(out, "error %v\n", err)
+			}
+		}
+	}()
+
+	go func() {
+		for ev := range events {
+			if ev.Type != p9.EventAdded {
+				continue
+			}
+			fsw.Add(strings.TrimPrefix(ev.Path, ctlPrefix))
+		}
+	}()
+
+	rescanCtl := func() {
+		data, _ := os.ReadFile("/dev/watch/ctl")
+		scanner := bufio.NewScanner(strings.NewReader(string(data)))
+		for scanner.Scan() {
+			p := strings.TrimSpace(scanner.Text())
+			if p == "" {
+				continue
+			}
+			if err := mux.Register(ctlPrefix+p, noopHandler{}); err != nil {
+				fmt.Fprintf(os.Stderr, "devwatcher: register %s: %v\n", p, err)
+			}
+		}
+	}
+
+	rescanCtl()
+	for {
+		select {
+		case <-ctlWatcher.Events:
+			rescanCtl()
+		case <-ctlWatcher.Errors:
+		case <-ctx.Done():
+			return
+		}
+	}
 }
@@ -1,7 +1,7 @@
 // CLASSIFICATION: COMMUNITY
-// Filename: main.go v0.5
+// Filename: main.go v0.9
 // Author: Lukas Bower
-// Date Modified: 2029-02-21
+// Date Modified: 2026-07-26
 // License: SPDX-License-Identifier: MIT OR Apache-2.0
 
 package main
@@ -13,18 +13,45 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
+	"cohesix/internal/orchestrator/api"
 	orchestrator "cohesix/internal/orchestrator/http"
 )
 
 type credentials struct {
-	User     string   `json:"user"`
-	Pass     string   `json:"pass"`
-	Roles    []string `json:"roles"`
-	TLSCert  string   `json:"tls_cert"`
-	TLSKey   string   `json:"tls_key"`
-	ClientCA string   `json:"client_ca"`
+	User             string                `json:"user"`
+	Pass             string                `json:"pass"`
+	Roles            []string              `json:"roles"`
+	TLSCert          string                `json:"tls_cert"`
+	TLSKey           string                `json:"tls_key"`
+	ClientCA         string                `json:"client_ca"`
+	IdentityBindings []api.IdentityBinding `json:"identity_bindings"`
+	TrustDomains     []string              `json:"trust_domains"`
+	StorageDriver    string                `json:"storage_driver"`
+	StorageEndpoints []string              `json:"storage_endpoints"`
+	StoragePrefix    string                `json:"storage_prefix"`
+	JWTPublicKey     string                `json:"jwt_public_key"`
+	JWTSecret        string                `json:"jwt_secret"`
+	JWTIssuer        string                `json:"jwt_issuer"`
+	JWTAudience      string                `json:"jwt_audience"`
+}
+
+// parseBuckets parses a comma-separated list of histogram bucket bounds
+// in seconds, e.g. "0.1,0.3,1.2,5".
+func parseBuckets(s string) ([]float64, error) {
+	fields := strings.Split(s, ",")
+	buckets := make([]float64, 0, len(fields))
+	for _, f := range fields {
+		v, err := strconv.ParseFloat(strings.TrimSpace(f), 64)
+		if err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, v)
+	}
+	return buckets, nil
 }
 
 func loadCreds(path string) (credentials, error) {
@@ -45,18 +72,47 @@ func main() {
 	port := flag.Int("port", 8888, "listen port")
 	staticDir := flag.String("static-dir", "static", "directory for static files")
 	logFile := flag.String("log-file", "/srv/trace/gui_access.log", "access log file")
+	logFormat := flag.String("log-format", "json", "access log encoding: json or text")
+	logMaxSizeMB := flag.Int("log-max-size-mb", 100, "rotate the access log out to a backup past this size; 0 disables size-based rotation")
+	logMaxBackups := flag.Int("log-max-backups", 10, "rotated access log backups to keep; 0 keeps them all")
+	logMaxAgeDays := flag.Int("log-max-age-days", 30, "days to keep rotated access log backups; 0 disables age-based cleanup")
 	dev := flag.Bool("dev", false, "enable developer mode")
+	wsMaxMessageBytes := flag.Int("ws-max-message-bytes", 4<<20, "max per-message buffer for /api/stream (and its /api/cluster/stream alias)")
+	metricsPath := flag.String("metrics-path", "/metrics", "path serving the Prometheus text-exposition format, alongside /api/metrics")
+	metricsBuckets := flag.String("metrics-buckets", "", "comma-separated http_request_duration_seconds histogram buckets in seconds; empty uses metrics.DefaultBuckets")
+	enrollEnabled := flag.Bool("enroll-enabled", false, "enable POST /api/enroll/token and /api/enroll for worker self-enrollment, signing CSRs with the tls_cert/tls_key from /srv/orch_user.json")
+	machinesFile := flag.String("machines-file", "/srv/orch_machines.json", "file persisting machine identities enrolled via /api/enroll")
+	statusStreamTick := flag.Duration("status-stream-tick", 2*time.Second, "how often /api/status/stream polls for worker/queen changes to push as events")
+	fetchTimeout := flag.Duration("fetch-timeout", api.DefaultFetchTimeout, "how long /api/status waits for a FetchClusterState call before giving up with a 504")
 	flag.Parse()
 
 	cfg := orchestrator.Config{
-		Bind:      *bind,
-		Port:      *port,
-		StaticDir: *staticDir,
-		LogFile:   *logFile,
-		Dev:       *dev,
+		Bind:                     *bind,
+		Port:                     *port,
+		StaticDir:                *staticDir,
+		LogFile:                  *logFile,
+		LogFormat:                *logFormat,
+		LogMaxSizeMB:             *logMaxSizeMB,
+		LogMaxBackups:            *logMaxBackups,
+		LogMaxAgeDays:            *logMaxAgeDays,
+		Dev:                      *dev,
+		WSMaxMessageBytes:        *wsMaxMessageBytes,
+		MetricsPath:              *metricsPath,
+		EnrollEnabled:            *enrollEnabled,
+		MachinesFile:             *machinesFile,
+		StatusStreamTickInterval: *statusStreamTick,
+		FetchTimeout:             *fetchTimeout,
 	}
 	cfg.RPCTimeout = 5 * time.Second
 
+	if *metricsBuckets != "" {
+		buckets, err := parseBuckets(*metricsBuckets)
+		if err != nil {
+			log.Fatalf("parse metrics-buckets: %v", err)
+		}
+		cfg.MetricsBuckets = buckets
+	}
+
 	if !cfg.Dev {
 		if creds, err := loadCreds("/srv/orch_user.json"); err == nil {
 			cfg.AuthUser = creds.User
@@ -73,6 +129,33 @@ func main() {
 			if creds.ClientCA != "" {
 				cfg.TLSClientCA = creds.ClientCA
 			}
+			if len(creds.IdentityBindings) > 0 {
+				cfg.IdentityBindings = creds.IdentityBindings
+			}
+			if len(creds.TrustDomains) > 0 {
+				cfg.TrustDomains = creds.TrustDomains
+			}
+			if creds.StorageDriver != "" {
+				cfg.StorageDriver = creds.StorageDriver
+			}
+			if len(creds.StorageEndpoints) > 0 {
+				cfg.StorageEndpoints = creds.StorageEndpoints
+			}
+			if creds.StoragePrefix != "" {
+				cfg.StoragePrefix = creds.StoragePrefix
+			}
+			if creds.JWTPublicKey != "" {
+				cfg.JWTPublicKey = creds.JWTPublicKey
+			}
+			if creds.JWTSecret != "" {
+				cfg.JWTSecret = creds.JWTSecret
+			}
+			if creds.JWTIssuer != "" {
+				cfg.JWTIssuer = creds.JWTIssuer
+			}
+			if creds.JWTAudience != "" {
+				cfg.JWTAudience = creds.JWTAudience
+			}
 		} else {
 			log.Printf("warning: could not load creds: %v", err)
 		}
@@ -86,9 +169,25 @@ func main() {
 	if err != nil {
 		log.Fatalf("initialise orchestrator: %v", err)
 	}
-	ctx, cancel := newSignalContext(context.Background())
+	ctx, cancel, reload := newSignalContext(context.Background())
 	defer cancel()
+	go watchReload(ctx, srv, reload)
 	if err := srv.Start(ctx); err != nil && err != http.ErrServerClosed {
 		log.Fatalf("server error: %v", err)
 	}
 }
+
+// watchReload redials the orchestrator gRPC gateway each time reload fires
+// (SIGHUP on Unix), until ctx is done.
+func watchReload(ctx context.Context, srv *orchestrator.Server, reload <-chan os.Signal) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-reload:
+			if err := srv.Reload(ctx); err != nil {
+				log.Printf("reload: %v", err)
+			}
+		}
+	}
+}
@@ -1,15 +1,23 @@
 // CLASSIFICATION: COMMUNITY
-// Filename: signal_plan9.go v0.1
+// Filename: signal_plan9.go v0.2
 // Author: Lukas Bower
-// Date Modified: 2026-12-31
+// Date Modified: 2026-07-26
 // License: SPDX-License-Identifier: MIT OR Apache-2.0
 
 //go:build plan9
 
 package main
 
-import "context"
+import (
+	"context"
+	"os"
+)
 
-func newSignalContext(ctx context.Context) (context.Context, context.CancelFunc) {
-	return context.WithCancel(ctx)
+// newSignalContext returns a context cancellable only via the returned
+// CancelFunc: plan9 has no signal package support, so there is no SIGINT/
+// SIGTERM to notify on and no reload trigger either -- the returned channel
+// never fires.
+func newSignalContext(ctx context.Context) (context.Context, context.CancelFunc, <-chan os.Signal) {
+	ctx, cancel := context.WithCancel(ctx)
+	return ctx, cancel, make(chan os.Signal)
 }
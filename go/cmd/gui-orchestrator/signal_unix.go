@@ -1,19 +1,26 @@
 // CLASSIFICATION: COMMUNITY
-// Filename: signal_unix.go v0.1
+// Filename: signal_unix.go v0.2
 // Author: Lukas Bower
-// Date Modified: 2026-12-31
+// Date Modified: 2026-07-26
 // License: SPDX-License-Identifier: MIT OR Apache-2.0
 
-//go:build !plan9
+//go:build !plan9 && !windows
 
 package main
 
 import (
 	"context"
+	"os"
 	"os/signal"
 	"syscall"
 )
 
-func newSignalContext(ctx context.Context) (context.Context, context.CancelFunc) {
-	return signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+// newSignalContext returns a context canceled on SIGINT/SIGTERM, plus a
+// channel that receives a value each time SIGHUP arrives so the caller can
+// drive a hot config reload instead of shutting down.
+func newSignalContext(ctx context.Context) (context.Context, context.CancelFunc, <-chan os.Signal) {
+	ctx, cancel := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	return ctx, cancel, reload
 }
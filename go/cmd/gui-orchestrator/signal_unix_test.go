@@ -0,0 +1,37 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: signal_unix_test.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+//go:build !plan9 && !windows
+
+package main
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestNewSignalContextDeliversSIGHUPOnReloadChannel(t *testing.T) {
+	ctx, cancel, reload := newSignalContext(context.Background())
+	defer cancel()
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatalf("send SIGHUP: %v", err)
+	}
+
+	select {
+	case <-reload:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for SIGHUP on reload channel")
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("SIGHUP must not cancel the shutdown context")
+	default:
+	}
+}
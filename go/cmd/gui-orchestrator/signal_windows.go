@@ -0,0 +1,29 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: signal_windows.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+//go:build windows
+
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// newSignalContext returns a context canceled on os.Interrupt (Ctrl+C),
+// plus a channel for a hot config reload trigger. Windows has no SIGHUP:
+// syscall.SIGHUP is accepted by signal.Notify here as a portable constant,
+// but in practice nothing raises it, so the reload channel does not fire
+// on Windows; it exists so the platforms share one newSignalContext
+// signature.
+func newSignalContext(ctx context.Context) (context.Context, context.CancelFunc, <-chan os.Signal) {
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	return ctx, cancel, reload
+}
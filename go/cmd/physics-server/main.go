@@ -7,12 +7,29 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
+
+	"cohesix/internal/agent_sdk/store"
+	"cohesix/internal/logging"
+)
+
+const (
+	envStateBackend  = "COHESIX_STATE_BACKEND"
+	envEtcdEndpoints = "COHESIX_ETCD_ENDPOINTS"
+	envRole          = "COHESIX_ROLE"
+	envAgentID       = "COHESIX_AGENT_ID"
+
+	// keyWorld must match agentsdk's world snapshot key so
+	// AgentContext.WorldSnapshotCopy observes what this worker publishes.
+	keyWorld  = "world_state/world.json"
+	keyStatus = "physics/status"
 )
 
 // PhysicsJob mirrors the physics_job.json schema.
@@ -39,9 +56,44 @@ type Result struct {
 	Logs     []string `json:"logs"`
 }
 
-func writeStatus(processed int, lastErr, lastJob string) {
+// openStore selects a Store implementation based on COHESIX_STATE_BACKEND
+// ("fs" or "etcd", default "fs"), mirroring agentsdk.New so the physics
+// worker and the agents reading its output agree on a backend.
+func openStore(logger logging.Logger) store.Store {
+	backend := strings.ToLower(strings.TrimSpace(os.Getenv(envStateBackend)))
+	if backend == "etcd" {
+		endpoints := strings.FieldsFunc(os.Getenv(envEtcdEndpoints), func(r rune) bool { return r == ',' })
+		if len(endpoints) > 0 {
+			role := os.Getenv(envRole)
+			if role == "" {
+				role = "PhysicsWorker"
+			}
+			agentID := os.Getenv(envAgentID)
+			if agentID == "" {
+				agentID = "unknown-physics-worker"
+			}
+			st, err := store.NewEtcdStore(context.Background(), role+"/"+agentID, store.EtcdOptions{Endpoints: endpoints})
+			if err == nil {
+				return st
+			}
+			logger.Warn("etcd store unavailable, falling back to filesystem", "error", err)
+		} else {
+			logger.Warn("etcd endpoints unset, falling back to filesystem", "env", envEtcdEndpoints)
+		}
+	}
+	st, err := store.NewFSStore("/srv")
+	if err != nil {
+		logger.Error("filesystem store unavailable", "error", err)
+		os.Exit(1)
+	}
+	return st
+}
+
+func writeStatus(logger logging.Logger, st store.Store, processed int, lastErr, lastJob string) {
 	status := fmt.Sprintf("jobs_processed=%d\nlast_error=\"%s\"\nlast_job=\"%s\"\n", processed, lastErr, lastJob)
-	os.WriteFile("/srv/physics/status", []byte(status), 0644)
+	if _, err := st.Put(context.Background(), keyStatus, []byte(status)); err != nil {
+		logger.Error("write status", "error", err)
+	}
 }
 
 func main() {
@@ -52,7 +104,10 @@ func main() {
 	if err != nil {
 		log.Fatalf("log open: %v", err)
 	}
-	logger := log.New(logFile, "", log.LstdFlags)
+	logger := logging.NewWithOutput("physics", logFile).With(logging.FieldRole, "PhysicsWorker")
+
+	st := openStore(logger)
+	defer st.Close()
 
 	processed := 0
 	lastErr := ""
@@ -63,16 +118,16 @@ func main() {
 		for _, jobPath := range matches {
 			data, err := os.ReadFile(jobPath)
 			if err != nil {
-				logger.Printf("read %s: %v", jobPath, err)
+				logger.Error("read job", "job_path", jobPath, "error", err)
 				lastErr = err.Error()
-				writeStatus(processed, lastErr, lastJob)
+				writeStatus(logger, st, processed, lastErr, lastJob)
 				continue
 			}
 			var job PhysicsJob
 			if err := json.Unmarshal(data, &job); err != nil {
-				logger.Printf("parse %s: %v", jobPath, err)
+				logger.Error("parse job", "job_path", jobPath, "error", err)
 				lastErr = err.Error()
-				writeStatus(processed, lastErr, lastJob)
+				writeStatus(logger, st, processed, lastErr, lastJob)
 				os.Remove(jobPath)
 				continue
 			}
@@ -85,17 +140,20 @@ func main() {
 			world := World{FinalPosition: finalPos, FinalVelocity: job.InitialVelocity, Collided: false, EnergyRemaining: 0.95}
 			wdata, _ := json.MarshalIndent(world, "", "  ")
 			os.WriteFile("/sim/world.json", wdata, 0644)
+			if _, err := st.Put(context.Background(), keyWorld, wdata); err != nil {
+				logger.Error("publish world state", "error", err)
+			}
 
 			result := Result{JobID: job.JobID, Status: "completed", Steps: steps, Duration: float64(steps) / 100.0,
 				Logs: []string{"t=0.1 pos=[0.1,0,0]", "t=0.2 pos=[0.2,0,0]"}}
 			rdata, _ := json.MarshalIndent(result, "", "  ")
 			os.WriteFile("/sim/result.json", rdata, 0644)
 
-			logger.Printf("completed %s", job.JobID)
+			logger.Info("completed", "job_id", job.JobID)
 			lastErr = ""
 			lastJob = fmt.Sprintf("%s @ %s", job.JobID, time.Now().Format("2006-01-02 15:04"))
 			processed++
-			writeStatus(processed, lastErr, lastJob)
+			writeStatus(logger, st, processed, lastErr, lastJob)
 			os.Remove(jobPath)
 		}
 		time.Sleep(2 * time.Second)
@@ -11,6 +11,10 @@ import (
     "fmt"
     "os"
     "path/filepath"
+    "strings"
+    "time"
+
+    "cohesix/internal/orchestrator/api"
 )
 
 func announce(args []string) {
@@ -30,15 +34,61 @@ func announce(args []string) {
     os.WriteFile(filepath.Join(srvDir, "ctl"), []byte{}, 0644)
 }
 
+func machines(args []string) {
+    fs := flag.NewFlagSet("machines", flag.ExitOnError)
+    file := fs.String("file", "/srv/orch_machines.json", "machine registry file")
+    fs.Parse(args)
+    rest := fs.Args()
+    if len(rest) < 1 {
+        fmt.Fprintln(os.Stderr, "usage: srvctl machines [-file path] list|validate|delete [machine_id]")
+        os.Exit(1)
+    }
+    registry, err := api.NewMachineRegistry(*file)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "load machine registry: %v\n", err)
+        os.Exit(1)
+    }
+    switch rest[0] {
+    case "list":
+        for _, m := range registry.List() {
+            fmt.Printf("%s\tvalidated=%v\troles=%s\tcert_fingerprint=%s\tenrolled_at=%s\n",
+                m.MachineID, m.Validated, strings.Join(m.Roles, ","), m.CertFingerprint, m.EnrolledAt.Format(time.RFC3339))
+        }
+    case "validate":
+        if len(rest) < 2 {
+            fmt.Fprintln(os.Stderr, "usage: srvctl machines validate <machine_id>")
+            os.Exit(1)
+        }
+        if err := registry.Validate(rest[1]); err != nil {
+            fmt.Fprintf(os.Stderr, "validate: %v\n", err)
+            os.Exit(1)
+        }
+    case "delete":
+        if len(rest) < 2 {
+            fmt.Fprintln(os.Stderr, "usage: srvctl machines delete <machine_id>")
+            os.Exit(1)
+        }
+        if err := registry.Delete(rest[1]); err != nil {
+            fmt.Fprintf(os.Stderr, "delete: %v\n", err)
+            os.Exit(1)
+        }
+    default:
+        fmt.Fprintln(os.Stderr, "usage: srvctl machines [-file path] list|validate|delete [machine_id]")
+        os.Exit(1)
+    }
+}
+
 func main() {
     if len(os.Args) < 2 {
-        fmt.Fprintln(os.Stderr, "usage: srvctl announce [args]")
+        fmt.Fprintln(os.Stderr, "usage: srvctl announce|machines [args]")
         os.Exit(1)
     }
     cmd := os.Args[1]
     switch cmd {
     case "announce":
         announce(os.Args[2:])
+    case "machines":
+        machines(os.Args[2:])
     default:
         fmt.Fprintln(os.Stderr, "unknown command")
         os.Exit(1)
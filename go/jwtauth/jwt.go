@@ -0,0 +1,193 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: jwt.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+// Package jwtauth mints and verifies the RS256 "Authorization: Bearer"
+// tokens Cohesix orchestrators use to authenticate cluster peers. It has no
+// dependency beyond the standard library so that agent_sdk (which mints
+// tokens) doesn't have to import orchestrator/api (which verifies them) just
+// to reach this logic.
+package jwtauth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrVerifyOnly signals that a JWTVerifier has no private key configured,
+// so MintToken refuses: the mode a worker orchestrator runs in, trusting
+// the queen's public key without ever holding its private key.
+var ErrVerifyOnly = errors.New("jwt verifier is in verify-only mode: no signing key configured")
+
+// ErrInvalidToken covers every bearer-token failure once it has parsed as a
+// JWT -- bad signature, expiry, issuer, or audience mismatch. Callers
+// return 401 without needing to distinguish further, the same way
+// Resolve's ErrNoIdentityBinding is handled by identityMiddleware.
+var ErrInvalidToken = errors.New("invalid or expired bearer token")
+
+// ErrMalformedToken indicates the bearer token never parsed as a JWT at
+// all (wrong segment count, or a segment that isn't valid base64url/JSON),
+// as opposed to ErrInvalidToken's "parsed fine, but failed verification".
+// JWTAuthFilter treats this as an abstention rather than a denial, so a
+// bearer token meant for a different scheme (e.g. StaticAllowListFilter)
+// falls through to it instead of being rejected outright.
+var ErrMalformedToken = errors.New("bearer token does not parse as a JWT")
+
+// JWTClaims is the RFC 7519 claim set Cohesix bearer tokens carry. Roles
+// is the non-standard claim identityMiddleware's SPIFFE path has no
+// equivalent of: the set of Cohesix roles this token is permitted to act
+// as, checked against a server's AllowedRoles gate.
+type JWTClaims struct {
+	Issuer    string   `json:"iss,omitempty"`
+	Audience  string   `json:"aud,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	Roles     []string `json:"roles,omitempty"`
+	IssuedAt  int64    `json:"iat"`
+	ExpiresAt int64    `json:"exp"`
+}
+
+// JWTVerifier verifies RS256 bearer tokens, and mints them when a private
+// key is configured. It is the bearer-token analogue of IdentityResolver:
+// where IdentityResolver maps a verified client certificate to a role,
+// JWTVerifier maps a verified "Authorization: Bearer" token to one.
+type JWTVerifier struct {
+	publicKey  *rsa.PublicKey
+	privateKey *rsa.PrivateKey
+	issuer     string
+	audience   string
+}
+
+// NewJWTVerifier parses PEM-encoded RSA key material. publicKeyPEM is
+// required. privateKeyPEM may be nil, in which case the verifier can
+// validate tokens but MintToken returns ErrVerifyOnly. issuer/audience,
+// when non-empty, are checked against each token's iss/aud claims.
+func NewJWTVerifier(publicKeyPEM, privateKeyPEM []byte, issuer, audience string) (*JWTVerifier, error) {
+	pub, err := parseRSAPublicKeyPEM(publicKeyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("parse jwt public key: %w", err)
+	}
+	v := &JWTVerifier{publicKey: pub, issuer: issuer, audience: audience}
+	if len(privateKeyPEM) > 0 {
+		priv, err := parseRSAPrivateKeyPEM(privateKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("parse jwt private key: %w", err)
+		}
+		v.privateKey = priv
+	}
+	return v, nil
+}
+
+func parseRSAPublicKeyPEM(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaPub, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("PEM block is not an RSA public key")
+	}
+	return rsaPub, nil
+}
+
+func parseRSAPrivateKeyPEM(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("PEM block is not an RSA private key")
+	}
+	return rsaKey, nil
+}
+
+// MintToken signs a bearer token for subject (typically a cluster peer's
+// agent ID) carrying roles, valid for ttl from now. It returns
+// ErrVerifyOnly if this JWTVerifier has no private key.
+func (v *JWTVerifier) MintToken(subject string, roles []string, ttl time.Duration) (string, error) {
+	if v.privateKey == nil {
+		return "", ErrVerifyOnly
+	}
+	now := time.Now()
+	claims := JWTClaims{
+		Issuer:    v.issuer,
+		Audience:  v.audience,
+		Subject:   subject,
+		Roles:     roles,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	}
+	headerJSON, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+	hash := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, v.privateKey, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// VerifyToken checks token's RS256 signature, expiry, and (when
+// configured) issuer/audience, returning its claims on success.
+func (v *JWTVerifier) VerifyToken(token string) (*JWTClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	var claims JWTClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrMalformedToken
+	}
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(v.publicKey, crypto.SHA256, hash[:], sig); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if claims.ExpiresAt != 0 && time.Now().Unix() > claims.ExpiresAt {
+		return nil, ErrInvalidToken
+	}
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return nil, ErrInvalidToken
+	}
+	if v.audience != "" && claims.Audience != v.audience {
+		return nil, ErrInvalidToken
+	}
+	return &claims, nil
+}
@@ -0,0 +1,140 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: jwt_test.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package jwtauth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+)
+
+func generateTestKeyPair(t *testing.T) (pubPEM, privPEM []byte) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	pubPEM = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+	privPEM = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return pubPEM, privPEM
+}
+
+func TestJWTVerifierMintAndVerifyRoundTrip(t *testing.T) {
+	pubPEM, privPEM := generateTestKeyPair(t)
+	verifier, err := NewJWTVerifier(pubPEM, privPEM, "cohesix-queen", "cohesix-cluster")
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+
+	token, err := verifier.MintToken("worker-a", []string{"DroneWorker"}, time.Minute)
+	if err != nil {
+		t.Fatalf("mint token: %v", err)
+	}
+	claims, err := verifier.VerifyToken(token)
+	if err != nil {
+		t.Fatalf("verify token: %v", err)
+	}
+	if claims.Subject != "worker-a" || len(claims.Roles) != 1 || claims.Roles[0] != "DroneWorker" {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+}
+
+func TestJWTVerifierVerifyOnlyModeRefusesMint(t *testing.T) {
+	pubPEM, _ := generateTestKeyPair(t)
+	verifier, err := NewJWTVerifier(pubPEM, nil, "", "")
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+	if _, err := verifier.MintToken("worker-a", []string{"DroneWorker"}, time.Minute); !errors.Is(err, ErrVerifyOnly) {
+		t.Fatalf("expected ErrVerifyOnly, got %v", err)
+	}
+}
+
+func TestJWTVerifierVerifyOnlyModeStillVerifies(t *testing.T) {
+	pubPEM, privPEM := generateTestKeyPair(t)
+	signer, err := NewJWTVerifier(pubPEM, privPEM, "", "")
+	if err != nil {
+		t.Fatalf("new signer: %v", err)
+	}
+	token, err := signer.MintToken("worker-a", []string{"DroneWorker"}, time.Minute)
+	if err != nil {
+		t.Fatalf("mint token: %v", err)
+	}
+
+	verifyOnly, err := NewJWTVerifier(pubPEM, nil, "", "")
+	if err != nil {
+		t.Fatalf("new verify-only verifier: %v", err)
+	}
+	if _, err := verifyOnly.VerifyToken(token); err != nil {
+		t.Fatalf("verify-only verifier should validate the queen's token: %v", err)
+	}
+}
+
+func TestJWTVerifierRejectsExpiredToken(t *testing.T) {
+	pubPEM, privPEM := generateTestKeyPair(t)
+	verifier, err := NewJWTVerifier(pubPEM, privPEM, "", "")
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+	token, err := verifier.MintToken("worker-a", []string{"DroneWorker"}, -time.Minute)
+	if err != nil {
+		t.Fatalf("mint token: %v", err)
+	}
+	if _, err := verifier.VerifyToken(token); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected ErrInvalidToken, got %v", err)
+	}
+}
+
+func TestJWTVerifierRejectsWrongIssuerAndAudience(t *testing.T) {
+	pubPEM, privPEM := generateTestKeyPair(t)
+	signer, err := NewJWTVerifier(pubPEM, privPEM, "wrong-issuer", "wrong-audience")
+	if err != nil {
+		t.Fatalf("new signer: %v", err)
+	}
+	token, err := signer.MintToken("worker-a", []string{"DroneWorker"}, time.Minute)
+	if err != nil {
+		t.Fatalf("mint token: %v", err)
+	}
+
+	verifier, err := NewJWTVerifier(pubPEM, nil, "cohesix-queen", "cohesix-cluster")
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+	if _, err := verifier.VerifyToken(token); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected ErrInvalidToken, got %v", err)
+	}
+}
+
+func TestJWTVerifierRejectsTamperedSignature(t *testing.T) {
+	pubPEMA, privPEMA := generateTestKeyPair(t)
+	pubPEMB, _ := generateTestKeyPair(t)
+
+	signer, err := NewJWTVerifier(pubPEMA, privPEMA, "", "")
+	if err != nil {
+		t.Fatalf("new signer: %v", err)
+	}
+	token, err := signer.MintToken("worker-a", []string{"DroneWorker"}, time.Minute)
+	if err != nil {
+		t.Fatalf("mint token: %v", err)
+	}
+
+	otherVerifier, err := NewJWTVerifier(pubPEMB, nil, "", "")
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+	if _, err := otherVerifier.VerifyToken(token); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected ErrInvalidToken, got %v", err)
+	}
+}
@@ -0,0 +1,33 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: interceptor.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-25
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package logging
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// UnaryClientInterceptor returns a grpc.UnaryClientInterceptor that logs
+// every outbound RPC's method and duration at Info, or the error at Error,
+// under log. Callers typically pass a Logger already tagged with
+// request-level fields (FieldCommand, FieldWorkerID, ...) via With so the
+// gRPC line correlates with the HTTP request that triggered it.
+func UnaryClientInterceptor(log Logger) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		elapsed := time.Since(start)
+		if err != nil {
+			log.Error("grpc call failed", "method", method, "duration", elapsed, "error", err)
+			return err
+		}
+		log.Info("grpc call", "method", method, "duration", elapsed)
+		return nil
+	}
+}
@@ -0,0 +1,103 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: logging.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-25
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+// Package logging provides the structured logger shared by agentsdk, the
+// physics worker, and the orchestrator control plane, built on
+// github.com/hashicorp/go-hclog. It replaces the hand-rolled
+// log.Printf/json.Marshal event records that used to differ by subsystem
+// with a single grep-able stream.
+package logging
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	envLogFormat = "COHESIX_LOG_FORMAT"
+	envLogLevel  = "COHESIX_LOG_LEVEL"
+)
+
+// Field names attached consistently across subsystems so operators can
+// grep a single stream for a role, trace, worker, or command regardless
+// of which component emitted the line.
+const (
+	FieldRole       = "role"
+	FieldTraceID    = "trace_id"
+	FieldWorkerID   = "worker_id"
+	FieldCommand    = "command"
+	FieldRemoteAddr = "remote_addr"
+	FieldStatusCode = "status_code"
+)
+
+// Logger is the structured logger used across cohesix. It satisfies any
+// interface expecting Printf(format string, v ...any) (e.g. the
+// orchestrator HTTP server's Logger), while also exposing hclog's leveled
+// and contextual logging.
+type Logger interface {
+	Trace(msg string, args ...any)
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+	Printf(format string, v ...any)
+
+	With(args ...any) Logger
+	Named(name string) Logger
+}
+
+type logger struct {
+	hclog.Logger
+}
+
+// New creates the root Logger for a subsystem, honoring COHESIX_LOG_FORMAT
+// ("json" or "logfmt", default "logfmt") and COHESIX_LOG_LEVEL (default
+// "info").
+func New(name string) Logger {
+	return &logger{hclog.New(&hclog.LoggerOptions{
+		Name:       name,
+		Level:      hclog.LevelFromString(levelFromEnv()),
+		JSONFormat: strings.EqualFold(strings.TrimSpace(os.Getenv(envLogFormat)), "json"),
+	})}
+}
+
+// NewWithOutput is New, but writes to w instead of stderr. Used by
+// subsystems (e.g. the physics worker) that already maintain their own
+// trace log file.
+func NewWithOutput(name string, w *os.File) Logger {
+	return &logger{hclog.New(&hclog.LoggerOptions{
+		Name:       name,
+		Level:      hclog.LevelFromString(levelFromEnv()),
+		JSONFormat: strings.EqualFold(strings.TrimSpace(os.Getenv(envLogFormat)), "json"),
+		Output:     w,
+	})}
+}
+
+func levelFromEnv() string {
+	level := strings.TrimSpace(os.Getenv(envLogLevel))
+	if level == "" {
+		return "info"
+	}
+	return level
+}
+
+// Printf satisfies callers (e.g. the orchestrator HTTP server's Logger)
+// that expect the standard-library log.Logger signature; it logs at info
+// level.
+func (l *logger) Printf(format string, v ...any) {
+	l.Logger.Info(fmt.Sprintf(format, v...))
+}
+
+func (l *logger) With(args ...any) Logger {
+	return &logger{l.Logger.With(args...)}
+}
+
+func (l *logger) Named(name string) Logger {
+	return &logger{l.Logger.Named(name)}
+}
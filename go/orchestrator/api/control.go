@@ -12,8 +12,16 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+
+	"cohesix/internal/logging"
 )
 
+// controlLog is package-scoped rather than threaded through Control's
+// signature: Control is a free function with no constructor of its own, so
+// there's nowhere natural to inject a per-instance logger without breaking
+// every existing caller.
+var controlLog = logging.New("orchestrator.control")
+
 // ControlRequest is a command sent to the orchestrator.
 type ControlRequest struct {
 	Command    string `json:"command"`
@@ -45,17 +53,23 @@ var ErrUnauthorizedRole = errors.New("unauthorized role")
 // Control handles POST /api/control requests.
 func Control(ctrl Controller, authorizer ControlAuthorizer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		log := controlLog.With(logging.FieldRemoteAddr, r.RemoteAddr)
+
 		var req ControlRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			log.Warn("invalid control request body", "error", err, logging.FieldStatusCode, http.StatusBadRequest)
 			http.Error(w, "invalid json", http.StatusBadRequest)
 			return
 		}
+		log = log.With(logging.FieldCommand, req.Command, logging.FieldWorkerID, req.WorkerID)
 		if ctrl == nil {
+			log.Error("control unavailable", logging.FieldStatusCode, http.StatusServiceUnavailable)
 			http.Error(w, "control unavailable", http.StatusServiceUnavailable)
 			return
 		}
 		req.Command = strings.TrimSpace(req.Command)
 		if req.Command == "" {
+			log.Warn("control request missing command", logging.FieldStatusCode, http.StatusBadRequest)
 			http.Error(w, "command required", http.StatusBadRequest)
 			return
 		}
@@ -65,6 +79,7 @@ func Control(ctrl Controller, authorizer ControlAuthorizer) http.HandlerFunc {
 				if errors.Is(err, ErrUnauthorizedRole) {
 					status = http.StatusForbidden
 				}
+				log.Warn("control request rejected by authorizer", "error", err, logging.FieldStatusCode, status)
 				http.Error(w, err.Error(), status)
 				return
 			}
@@ -74,12 +89,18 @@ func Control(ctrl Controller, authorizer ControlAuthorizer) http.HandlerFunc {
 			switch {
 			case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
 				status = http.StatusGatewayTimeout
+			case errors.Is(err, ErrGatewayUnhealthy):
+				status = http.StatusServiceUnavailable
+			case errors.Is(err, ErrMachineNotValidated):
+				status = http.StatusForbidden
 			default:
 				status = http.StatusBadGateway
 			}
+			log.Error("control request failed", "error", err, logging.FieldStatusCode, status)
 			http.Error(w, err.Error(), status)
 			return
 		}
+		log.Info("control request ack", logging.FieldStatusCode, http.StatusOK)
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(AckResponse{Status: "ack"})
 	}
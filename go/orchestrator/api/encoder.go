@@ -0,0 +1,193 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: encoder.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Encoder serializes a StatusResponse onto w, reporting its own
+// Content-Type so Status doesn't have to special-case each implementation.
+// Status picks one per request via encoderForRequest, so adding a Protobuf
+// or CBOR Encoder later (selected the same way, via an Accept "enc"
+// parameter) doesn't require touching the handler itself.
+type Encoder interface {
+	ContentType() string
+	Encode(w io.Writer, resp StatusResponse) error
+}
+
+// jsonEncoder is the default Encoder, and the only one every client can
+// assume is always available.
+type jsonEncoder struct{}
+
+func (jsonEncoder) ContentType() string { return "application/json" }
+
+func (jsonEncoder) Encode(w io.Writer, resp StatusResponse) error {
+	return json.NewEncoder(w).Encode(resp)
+}
+
+// fastEncoder hand-writes the same JSON encoding/json would produce, using
+// strconv instead of reflection. StatusResponse's shape is fixed and public
+// (see status.schema.json), so there's no need to pay encoding/json's
+// per-field reflection cost on /api/status's hot polling path once a
+// client has opted in via Accept: application/json;enc=fast.
+type fastEncoder struct{}
+
+func (fastEncoder) ContentType() string { return "application/json" }
+
+func (fastEncoder) Encode(w io.Writer, resp StatusResponse) error {
+	buf := make([]byte, 0, 512)
+	buf = append(buf, '{')
+	buf = appendJSONField(buf, "uptime", true)
+	buf = appendJSONString(buf, resp.Uptime)
+	buf = appendJSONField(buf, "status", false)
+	buf = appendJSONString(buf, resp.Status)
+	buf = appendJSONField(buf, "role", false)
+	buf = appendJSONString(buf, resp.Role)
+	buf = appendJSONField(buf, "queen_id", false)
+	buf = appendJSONString(buf, resp.QueenID)
+	buf = appendJSONField(buf, "workers", false)
+	buf = strconv.AppendInt(buf, int64(resp.Workers), 10)
+	buf = appendJSONField(buf, "generated_at", false)
+	buf = strconv.AppendUint(buf, resp.GeneratedAt, 10)
+	buf = appendJSONField(buf, "timeout_seconds", false)
+	buf = strconv.AppendUint(buf, uint64(resp.TimeoutSeconds), 10)
+	buf = appendJSONField(buf, "worker_statuses", false)
+	buf = appendWorkerSummaries(buf, resp.WorkerStatuses)
+	buf = append(buf, '}', '\n')
+	_, err := w.Write(buf)
+	return err
+}
+
+// appendWorkerSummaries writes null for a nil slice rather than "[]", to
+// match what encoding/json does for a nil []WorkerSummary -- BuildStatusResponse
+// leaves WorkerStatuses nil when a fetch returns no workers at all.
+func appendWorkerSummaries(buf []byte, workers []WorkerSummary) []byte {
+	if workers == nil {
+		return append(buf, 'n', 'u', 'l', 'l')
+	}
+	buf = append(buf, '[')
+	for i, wkr := range workers {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = appendWorkerSummary(buf, wkr)
+	}
+	return append(buf, ']')
+}
+
+func appendWorkerSummary(buf []byte, wkr WorkerSummary) []byte {
+	buf = append(buf, '{')
+	buf = appendJSONField(buf, "worker_id", true)
+	buf = appendJSONString(buf, wkr.WorkerID)
+	buf = appendJSONField(buf, "role", false)
+	buf = appendJSONString(buf, wkr.Role)
+	buf = appendJSONField(buf, "status", false)
+	buf = appendJSONString(buf, wkr.Status)
+	buf = appendJSONField(buf, "ip", false)
+	buf = appendJSONString(buf, wkr.IP)
+	buf = appendJSONField(buf, "trust", false)
+	buf = appendJSONString(buf, wkr.Trust)
+	buf = appendJSONField(buf, "boot_ts", false)
+	buf = strconv.AppendUint(buf, wkr.BootTS, 10)
+	buf = appendJSONField(buf, "last_seen", false)
+	buf = strconv.AppendUint(buf, wkr.LastSeen, 10)
+	buf = appendJSONField(buf, "capabilities", false)
+	if wkr.Capabilities == nil {
+		buf = append(buf, 'n', 'u', 'l', 'l')
+	} else {
+		buf = append(buf, '[')
+		for i, c := range wkr.Capabilities {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = appendJSONString(buf, c)
+		}
+		buf = append(buf, ']')
+	}
+	if wkr.GPU != nil {
+		buf = appendJSONField(buf, "gpu", false)
+		buf = appendGPUSummary(buf, *wkr.GPU)
+	}
+	buf = append(buf, '}')
+	return buf
+}
+
+func appendGPUSummary(buf []byte, gpu GPUSummary) []byte {
+	buf = append(buf, '{')
+	buf = appendJSONField(buf, "perf_watt", true)
+	buf = strconv.AppendFloat(buf, float64(gpu.PerfWatt), 'g', -1, 32)
+	buf = appendJSONField(buf, "mem_total", false)
+	buf = strconv.AppendUint(buf, gpu.MemTotal, 10)
+	buf = appendJSONField(buf, "mem_free", false)
+	buf = strconv.AppendUint(buf, gpu.MemFree, 10)
+	buf = appendJSONField(buf, "last_temp", false)
+	buf = strconv.AppendUint(buf, uint64(gpu.LastTemp), 10)
+	buf = appendJSONField(buf, "gpu_capacity", false)
+	buf = strconv.AppendUint(buf, uint64(gpu.GPUCapacity), 10)
+	buf = appendJSONField(buf, "current_load", false)
+	buf = strconv.AppendUint(buf, uint64(gpu.CurrentLoad), 10)
+	buf = appendJSONField(buf, "latency_score", false)
+	buf = strconv.AppendUint(buf, uint64(gpu.LatencyScore), 10)
+	buf = append(buf, '}')
+	return buf
+}
+
+// appendJSONField appends a comma (unless first, the leading field after
+// "{") followed by the quoted key and a colon.
+func appendJSONField(buf []byte, key string, first bool) []byte {
+	if !first {
+		buf = append(buf, ',')
+	}
+	buf = appendJSONString(buf, key)
+	buf = append(buf, ':')
+	return buf
+}
+
+// appendJSONString appends s as a double-quoted JSON string, escaping '"',
+// '\\' and control characters as \u00XX and replacing invalid UTF-8 with
+// U+FFFD. It leaves '<', '>' and '&' unescaped, which encoding/json would
+// escape; both forms decode to the same string.
+func appendJSONString(buf []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	buf = append(buf, '"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			buf = append(buf, '\\', byte(r))
+		case r < 0x20:
+			buf = append(buf, '\\', 'u', '0', '0', hex[r>>4], hex[r&0xF])
+		default:
+			buf = utf8.AppendRune(buf, r)
+		}
+	}
+	return append(buf, '"')
+}
+
+// encoderForRequest selects an Encoder from the request's Accept header,
+// e.g. "Accept: application/json;enc=fast" opts into fastEncoder; anything
+// else (including a bare "application/json" or no Accept header at all)
+// gets the default jsonEncoder.
+func encoderForRequest(r *http.Request) Encoder {
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return jsonEncoder{}
+	}
+	_, params, err := mime.ParseMediaType(accept)
+	if err != nil {
+		return jsonEncoder{}
+	}
+	if params["enc"] == "fast" {
+		return fastEncoder{}
+	}
+	return jsonEncoder{}
+}
@@ -0,0 +1,93 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: encoder_test.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func sampleStatusResponse() StatusResponse {
+	return StatusResponse{
+		Uptime:         "1h0m0s",
+		Status:         "ok",
+		Role:           "Queen",
+		QueenID:        "queen-a",
+		Workers:        1,
+		GeneratedAt:    42,
+		TimeoutSeconds: 5,
+		WorkerStatuses: []WorkerSummary{
+			{
+				WorkerID:     "worker-a",
+				Role:         "DroneWorker",
+				Status:       "ready",
+				IP:           "10.0.0.1",
+				Trust:        "green",
+				BootTS:       1,
+				LastSeen:     2,
+				Capabilities: []string{"cuda", "rocm"},
+				GPU: &GPUSummary{
+					PerfWatt:     12.5,
+					MemTotal:     1024,
+					MemFree:      512,
+					LastTemp:     50,
+					GPUCapacity:  100,
+					CurrentLoad:  80,
+					LatencyScore: 3,
+				},
+			},
+			{WorkerID: "worker-b", Capabilities: nil},
+		},
+	}
+}
+
+func TestFastEncoderMatchesJSONEncoder(t *testing.T) {
+	resp := sampleStatusResponse()
+
+	var jsonBuf, fastBuf bytes.Buffer
+	if err := (jsonEncoder{}).Encode(&jsonBuf, resp); err != nil {
+		t.Fatalf("jsonEncoder: %v", err)
+	}
+	if err := (fastEncoder{}).Encode(&fastBuf, resp); err != nil {
+		t.Fatalf("fastEncoder: %v", err)
+	}
+
+	var fromJSON, fromFast map[string]any
+	if err := json.Unmarshal(jsonBuf.Bytes(), &fromJSON); err != nil {
+		t.Fatalf("decode json encoding: %v", err)
+	}
+	if err := json.Unmarshal(fastBuf.Bytes(), &fromFast); err != nil {
+		t.Fatalf("decode fast encoding: %v", err)
+	}
+	if !reflect.DeepEqual(fromJSON, fromFast) {
+		t.Fatalf("fastEncoder output diverged from jsonEncoder:\njson: %#v\nfast: %#v", fromJSON, fromFast)
+	}
+}
+
+func TestEncoderForRequestSelectsFastOnAcceptParam(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/status", nil)
+	req.Header.Set("Accept", "application/json;enc=fast")
+	if _, ok := encoderForRequest(req).(fastEncoder); !ok {
+		t.Fatalf("expected fastEncoder, got %T", encoderForRequest(req))
+	}
+}
+
+func TestEncoderForRequestDefaultsToJSON(t *testing.T) {
+	cases := []string{"", "application/json", "text/html"}
+	for _, accept := range cases {
+		req := httptest.NewRequest("GET", "/api/status", nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if _, ok := encoderForRequest(req).(jsonEncoder); !ok {
+			t.Fatalf("Accept %q: expected jsonEncoder, got %T", accept, encoderForRequest(req))
+		}
+	}
+}
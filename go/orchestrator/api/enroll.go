@@ -0,0 +1,174 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: enroll.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package api
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// EnrollToken is a short-lived, single-use credential a Queen issues so a
+// new worker can self-enroll without pre-provisioned creds. See
+// EnrollTokenIssuer.Issue and EnrollTokenIssuer.Redeem.
+type EnrollToken struct {
+	Token      string
+	Roles      []string
+	TrustLevel string
+	ExpiresAt  time.Time
+}
+
+// ErrEnrollTokenInvalid signals that an enrollment token is unknown,
+// already redeemed, or expired.
+var ErrEnrollTokenInvalid = errors.New("enrollment token invalid or expired")
+
+// EnrollTokenIssuer mints and redeems enrollment tokens in memory -- these
+// are meant to live minutes, not survive a restart, so unlike
+// MachineRegistry there is no on-disk persistence.
+type EnrollTokenIssuer struct {
+	mu     sync.Mutex
+	tokens map[string]EnrollToken
+}
+
+// NewEnrollTokenIssuer returns an issuer with no tokens outstanding.
+func NewEnrollTokenIssuer() *EnrollTokenIssuer {
+	return &EnrollTokenIssuer{tokens: make(map[string]EnrollToken)}
+}
+
+// Issue mints a new enrollment token carrying roles/trustLevel, valid for
+// ttl from now.
+func (i *EnrollTokenIssuer) Issue(roles []string, trustLevel string, ttl time.Duration) (EnrollToken, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return EnrollToken{}, fmt.Errorf("generate enrollment token: %w", err)
+	}
+	tok := EnrollToken{
+		Token:      base64.RawURLEncoding.EncodeToString(raw),
+		Roles:      append([]string(nil), roles...),
+		TrustLevel: trustLevel,
+		ExpiresAt:  time.Now().Add(ttl),
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.tokens[tok.Token] = tok
+	return tok, nil
+}
+
+// Redeem consumes token exactly once -- a second Redeem of the same token,
+// or one past its ExpiresAt, returns ErrEnrollTokenInvalid.
+func (i *EnrollTokenIssuer) Redeem(token string) (EnrollToken, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	tok, ok := i.tokens[token]
+	if !ok {
+		return EnrollToken{}, ErrEnrollTokenInvalid
+	}
+	delete(i.tokens, token)
+	if time.Now().After(tok.ExpiresAt) {
+		return EnrollToken{}, ErrEnrollTokenInvalid
+	}
+	return tok, nil
+}
+
+// CertFingerprint returns the SHA-256 fingerprint of cert's raw DER
+// encoding, hex-encoded, the value stored in MachineIdentity.CertFingerprint
+// and matched against incoming mTLS connections.
+func CertFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// SignEnrollmentCSR signs csrPEM (a PKCS#10 certificate signing request)
+// with the Queen's own CA certificate and key -- the same certificate and
+// key buildTLSConfig loads as the server's TLS identity, reused here as a
+// signing CA -- returning the issued client certificate (PEM) and its
+// CertFingerprint.
+func SignEnrollmentCSR(csrPEM, caCertPEM, caKeyPEM []byte, ttl time.Duration) (certPEM []byte, fingerprint string, err error) {
+	block, _ := pem.Decode(csrPEM)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, "", errors.New("invalid certificate signing request")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, "", fmt.Errorf("parse csr: %w", err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, "", fmt.Errorf("csr signature: %w", err)
+	}
+
+	caCert, caKey, err := parseCAKeyPair(caCertPEM, caKeyPEM)
+	if err != nil {
+		return nil, "", err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, "", fmt.Errorf("generate serial: %w", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      csr.Subject,
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(ttl),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, csr.PublicKey, caKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("issue certificate: %w", err)
+	}
+	sum := sha256.Sum256(der)
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	return certPEM, hex.EncodeToString(sum[:]), nil
+}
+
+func parseCAKeyPair(certPEM, keyPEM []byte) (*x509.Certificate, crypto.Signer, error) {
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
+		return nil, nil, errors.New("invalid ca certificate")
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse ca certificate: %w", err)
+	}
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return nil, nil, errors.New("invalid ca key")
+	}
+	signer, err := parseCASigner(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse ca key: %w", err)
+	}
+	return cert, signer, nil
+}
+
+func parseCASigner(der []byte) (crypto.Signer, error) {
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("unsupported ca key type")
+	}
+	return signer, nil
+}
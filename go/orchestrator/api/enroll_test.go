@@ -0,0 +1,132 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: enroll_test.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestEnrollTokenIssuerRedeemIsSingleUse(t *testing.T) {
+	issuer := NewEnrollTokenIssuer()
+	tok, err := issuer.Issue([]string{"DroneWorker"}, "low", time.Minute)
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	if _, err := issuer.Redeem(tok.Token); err != nil {
+		t.Fatalf("first redeem: %v", err)
+	}
+	if _, err := issuer.Redeem(tok.Token); !errors.Is(err, ErrEnrollTokenInvalid) {
+		t.Fatalf("expected ErrEnrollTokenInvalid on second redeem, got %v", err)
+	}
+}
+
+func TestEnrollTokenIssuerRedeemRejectsExpired(t *testing.T) {
+	issuer := NewEnrollTokenIssuer()
+	tok, err := issuer.Issue([]string{"DroneWorker"}, "low", -time.Minute)
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	if _, err := issuer.Redeem(tok.Token); !errors.Is(err, ErrEnrollTokenInvalid) {
+		t.Fatalf("expected ErrEnrollTokenInvalid for expired token, got %v", err)
+	}
+}
+
+func TestEnrollTokenIssuerRedeemRejectsUnknownToken(t *testing.T) {
+	issuer := NewEnrollTokenIssuer()
+	if _, err := issuer.Redeem("no-such-token"); !errors.Is(err, ErrEnrollTokenInvalid) {
+		t.Fatalf("expected ErrEnrollTokenInvalid for unknown token, got %v", err)
+	}
+}
+
+// generateTestCA returns a self-signed CA certificate and key, PEM-encoded,
+// standing in for the server's own TLS cert/key that EnrollEnabled reuses as
+// a signing CA.
+func generateTestCA(t *testing.T) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ca key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create ca certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal ca key: %v", err)
+	}
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM
+}
+
+func generateTestCSR(t *testing.T) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate worker key: %v", err)
+	}
+	template := &x509.CertificateRequest{Subject: pkix.Name{CommonName: "worker-1"}}
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		t.Fatalf("create csr: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})
+}
+
+func TestSignEnrollmentCSRIssuesClientCert(t *testing.T) {
+	caCertPEM, caKeyPEM := generateTestCA(t)
+	csrPEM := generateTestCSR(t)
+
+	certPEM, fingerprint, err := SignEnrollmentCSR(csrPEM, caCertPEM, caKeyPEM, time.Hour)
+	if err != nil {
+		t.Fatalf("sign csr: %v", err)
+	}
+	if fingerprint == "" {
+		t.Fatal("expected non-empty fingerprint")
+	}
+
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		t.Fatal("expected decodable certificate PEM")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("parse issued certificate: %v", err)
+	}
+	if cert.Subject.CommonName != "worker-1" {
+		t.Fatalf("unexpected subject: %s", cert.Subject.CommonName)
+	}
+	if got := CertFingerprint(cert); got != fingerprint {
+		t.Fatalf("fingerprint mismatch: %s != %s", got, fingerprint)
+	}
+}
+
+func TestSignEnrollmentCSRRejectsMalformedCSR(t *testing.T) {
+	caCertPEM, caKeyPEM := generateTestCA(t)
+	if _, _, err := SignEnrollmentCSR([]byte("not a csr"), caCertPEM, caKeyPEM, time.Hour); err == nil {
+		t.Fatal("expected an error for a malformed csr")
+	}
+}
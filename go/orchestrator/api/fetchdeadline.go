@@ -0,0 +1,132 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: fetchdeadline.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"cohesix/internal/logging"
+	"cohesix/internal/orchestrator/rpc"
+)
+
+var fetchDeadlineLog = logging.New("orchestrator.fetchdeadline")
+
+// DefaultFetchTimeout bounds how long DeadlineClusterStateClient waits for
+// FetchClusterState when Config.FetchTimeout is left unset.
+const DefaultFetchTimeout = 5 * time.Second
+
+// FetchTimeoutError is returned by DeadlineClusterStateClient.FetchClusterState
+// when the underlying fetch does not win the race against the deadline.
+// Status renders it as a 504 with a structured JSON body instead of the
+// generic 502 plaintext error other failures get.
+type FetchTimeoutError struct {
+	// Reason is "shutdown" if Shutdown has fired by the time the fetch
+	// returns, otherwise "deadline elapsed". A fetch cut short by the
+	// caller's own context returns that context's error instead.
+	Reason  string
+	Elapsed time.Duration
+}
+
+func (e *FetchTimeoutError) Error() string {
+	return fmt.Sprintf("fetch_timeout: %s after %s", e.Reason, e.Elapsed)
+}
+
+// deadlineTimer closes cancelCh exactly once, whichever comes first of its
+// timer firing or an explicit cancel() call -- the same shared-channel
+// pattern gVisor's netstack deadlineTimer uses to let a read/write race a
+// SetDeadline call without each call site juggling its own timer.
+type deadlineTimer struct {
+	cancelCh chan struct{}
+	once     sync.Once
+	timer    *time.Timer
+}
+
+func newDeadlineTimer(timeout time.Duration) *deadlineTimer {
+	d := &deadlineTimer{cancelCh: make(chan struct{})}
+	d.timer = time.AfterFunc(timeout, d.cancel)
+	return d
+}
+
+func (d *deadlineTimer) cancel() {
+	d.once.Do(func() { close(d.cancelCh) })
+}
+
+func (d *deadlineTimer) stop() {
+	d.timer.Stop()
+}
+
+// DeadlineClusterStateClient wraps a ClusterStateClient so a slow gRPC peer
+// can't starve HTTP workers indefinitely: every FetchClusterState call races
+// the underlying fetch against a shared cancelCh that closes on whichever
+// comes first of FetchTimeout elapsing, the caller's context being
+// canceled, or Shutdown firing (the context newSignalContext produces,
+// wired in by the Server at startup).
+type DeadlineClusterStateClient struct {
+	client       ClusterStateClient
+	FetchTimeout time.Duration
+	// Shutdown, if non-nil, closing cancels any fetch still in flight --
+	// used to stop waiting on the orchestrator once the process itself is
+	// shutting down rather than leaking the call past its usefulness.
+	Shutdown <-chan struct{}
+}
+
+// NewDeadlineClusterStateClient returns a DeadlineClusterStateClient
+// wrapping client. A non-positive timeout falls back to
+// DefaultFetchTimeout. shutdown may be nil, meaning no global shutdown
+// signal cancels an in-flight fetch.
+func NewDeadlineClusterStateClient(client ClusterStateClient, timeout time.Duration, shutdown <-chan struct{}) *DeadlineClusterStateClient {
+	if timeout <= 0 {
+		timeout = DefaultFetchTimeout
+	}
+	return &DeadlineClusterStateClient{client: client, FetchTimeout: timeout, Shutdown: shutdown}
+}
+
+// FetchClusterState implements ClusterStateClient, returning a
+// *FetchTimeoutError if the deadline wins the race.
+func (d *DeadlineClusterStateClient) FetchClusterState(ctx context.Context) (*rpc.ClusterStateResponse, error) {
+	start := time.Now()
+	fetchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	dt := newDeadlineTimer(d.FetchTimeout)
+	defer dt.stop()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-dt.cancelCh:
+			cancel()
+		case <-ctx.Done():
+			cancel()
+		case <-d.Shutdown:
+			cancel()
+		case <-done:
+		}
+	}()
+
+	state, err := d.client.FetchClusterState(fetchCtx)
+	if err == nil || fetchCtx.Err() == nil || ctx.Err() != nil {
+		// Either it succeeded, failed for a reason unrelated to our own
+		// cancellation, or the caller's own context died first (in which
+		// case the caller already knows why and the plain error is enough).
+		return state, err
+	}
+
+	elapsed := time.Since(start)
+	reason := "deadline elapsed"
+	select {
+	case <-d.Shutdown:
+		reason = "shutdown"
+	default:
+	}
+	fetchDeadlineLog.Warn("cluster state fetch canceled", "reason", reason, "elapsed_ms", elapsed.Milliseconds())
+	return nil, &FetchTimeoutError{Reason: reason, Elapsed: elapsed}
+}
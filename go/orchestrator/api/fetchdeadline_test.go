@@ -0,0 +1,104 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: fetchdeadline_test.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cohesix/internal/orchestrator/rpc"
+)
+
+// slowClusterStateClient blocks until released (or its context is done)
+// before returning state, simulating a gRPC peer that never answers in
+// time.
+type slowClusterStateClient struct {
+	release chan struct{}
+	state   *rpc.ClusterStateResponse
+}
+
+func (c *slowClusterStateClient) FetchClusterState(ctx context.Context) (*rpc.ClusterStateResponse, error) {
+	select {
+	case <-c.release:
+		return c.state, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestDeadlineClusterStateClientReturnsFetchTimeoutErrorOnDeadline(t *testing.T) {
+	client := &slowClusterStateClient{release: make(chan struct{})}
+	defer close(client.release)
+	d := NewDeadlineClusterStateClient(client, 10*time.Millisecond, nil)
+
+	_, err := d.FetchClusterState(context.Background())
+	var timeoutErr *FetchTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected *FetchTimeoutError, got %v", err)
+	}
+	if timeoutErr.Reason != "deadline elapsed" {
+		t.Fatalf("unexpected reason: %q", timeoutErr.Reason)
+	}
+}
+
+func TestDeadlineClusterStateClientPassesThroughCallerCancellation(t *testing.T) {
+	client := &slowClusterStateClient{release: make(chan struct{})}
+	defer close(client.release)
+	d := NewDeadlineClusterStateClient(client, time.Minute, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := d.FetchClusterState(ctx)
+	var timeoutErr *FetchTimeoutError
+	if errors.As(err, &timeoutErr) {
+		t.Fatalf("expected the caller's own context error, got *FetchTimeoutError: %v", timeoutErr)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestDeadlineClusterStateClientCancelsOnShutdown(t *testing.T) {
+	client := &slowClusterStateClient{release: make(chan struct{})}
+	defer close(client.release)
+	shutdown := make(chan struct{})
+	close(shutdown)
+	d := NewDeadlineClusterStateClient(client, time.Minute, shutdown)
+
+	_, err := d.FetchClusterState(context.Background())
+	var timeoutErr *FetchTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected *FetchTimeoutError, got %v", err)
+	}
+	if timeoutErr.Reason != "shutdown" {
+		t.Fatalf("unexpected reason: %q", timeoutErr.Reason)
+	}
+}
+
+func TestDeadlineClusterStateClientReturnsResultWhenFastEnough(t *testing.T) {
+	want := &rpc.ClusterStateResponse{QueenId: "queen-a"}
+	client := &slowClusterStateClient{release: make(chan struct{}), state: want}
+	close(client.release)
+	d := NewDeadlineClusterStateClient(client, time.Minute, nil)
+
+	got, err := d.FetchClusterState(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != want {
+		t.Fatalf("unexpected state: %+v", got)
+	}
+}
+
+func TestDeadlineClusterStateClientDefaultsNonPositiveTimeout(t *testing.T) {
+	d := NewDeadlineClusterStateClient(&slowClusterStateClient{release: make(chan struct{})}, 0, nil)
+	if d.FetchTimeout != DefaultFetchTimeout {
+		t.Fatalf("expected DefaultFetchTimeout, got %v", d.FetchTimeout)
+	}
+}
@@ -1,7 +1,7 @@
 // CLASSIFICATION: COMMUNITY
-// Filename: gateway.go v0.1
+// Filename: gateway.go v0.2
 // Author: Lukas Bower
-// Date Modified: 2029-02-15
+// Date Modified: 2026-07-26
 // License: SPDX-License-Identifier: MIT OR Apache-2.0
 
 package api
@@ -16,11 +16,15 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"cohesix/internal/logging"
 	"cohesix/internal/orchestrator/rpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health/grpc_health_v1"
 )
 
 const (
@@ -31,6 +35,31 @@ const (
 	envOrchCACert    = "COHESIX_ORCH_CA_CERT"
 	envOrchClientCrt = "COHESIX_ORCH_CLIENT_CERT"
 	envOrchClientKey = "COHESIX_ORCH_CLIENT_KEY"
+
+	// orchestratorHealthService is the service name the orchestrator
+	// registers with grpc.health.v1.Health.
+	orchestratorHealthService = "cohesix.orchestrator"
+
+	// defaultServiceConfig sets an exponential backoff retry policy for the
+	// idempotent RPCs GetClusterState and UpdateTrust, which are read-only
+	// or naturally idempotent (re-asserting a trust level). It leaves the
+	// load-balancing policy at gRPC's default, and AssignRole and
+	// RequestSchedule are not retried.
+	defaultServiceConfig = `{
+		"methodConfig": [{
+			"name": [
+				{"service": "cohesix.orchestrator.OrchestratorService", "method": "GetClusterState"},
+				{"service": "cohesix.orchestrator.OrchestratorService", "method": "UpdateTrust"}
+			],
+			"retryPolicy": {
+				"MaxAttempts": 5,
+				"InitialBackoff": "0.2s",
+				"MaxBackoff": "5s",
+				"BackoffMultiplier": 2.0,
+				"RetryableStatusCodes": ["UNAVAILABLE"]
+			}
+		}]
+	}`
 )
 
 // ClusterStateClient fetches the current orchestrator cluster state.
@@ -38,6 +67,16 @@ type ClusterStateClient interface {
 	FetchClusterState(ctx context.Context) (*rpc.ClusterStateResponse, error)
 }
 
+// ClusterStateWatcher streams orchestrator cluster state as it changes,
+// rather than requiring the caller to poll FetchClusterState. Implemented
+// by GRPCGateway and consumed by orchestrator/http's WebSocket bridge at
+// /api/cluster/stream; kept separate from ClusterStateClient so the many
+// existing FetchClusterState-only test doubles don't need a streaming
+// method added to compile.
+type ClusterStateWatcher interface {
+	WatchClusterState(ctx context.Context) (rpc.OrchestratorService_WatchClusterStateClient, error)
+}
+
 // Gateway defines the behaviour expected from a gRPC-backed controller.
 type Gateway interface {
 	Controller
@@ -45,11 +84,21 @@ type Gateway interface {
 	Close() error
 }
 
+// ErrGatewayUnhealthy is returned by Execute and FetchClusterState while the
+// background health checker has observed the orchestrator as NOT_SERVING,
+// so callers don't block waiting on a connection known to be down.
+var ErrGatewayUnhealthy = errors.New("orchestrator gateway unhealthy")
+
 // GRPCGateway routes HTTP requests through the tonic gRPC orchestrator.
 type GRPCGateway struct {
 	client     rpc.OrchestratorServiceClient
 	conn       *grpc.ClientConn
 	rpcTimeout time.Duration
+	log        logging.Logger
+
+	healthy    atomic.Bool
+	stopHealth context.CancelFunc
+	healthDone chan struct{}
 }
 
 // NewGRPCGatewayFromEnv initialises the gateway using documented env vars.
@@ -61,26 +110,123 @@ func NewGRPCGatewayFromEnv(ctx context.Context, timeout time.Duration) (*GRPCGat
 	return NewGRPCGateway(ctx, endpoint, timeout)
 }
 
-// NewGRPCGateway creates a gateway targeting a custom endpoint.
+// NewGRPCGateway creates a gateway targeting a custom endpoint. Dialing is
+// lazy (the connection is established on first RPC, or in the background by
+// the health checker) so a transient orchestrator restart no longer wedges
+// construction behind grpc.WithBlock.
 func NewGRPCGateway(ctx context.Context, endpoint string, timeout time.Duration) (*GRPCGateway, error) {
 	if timeout <= 0 {
 		timeout = 5 * time.Second
 	}
-	dialCtx, cancel := context.WithTimeout(ctx, timeout)
-	defer cancel()
-
-	conn, err := dialOrchestrator(dialCtx, endpoint)
+	log := logging.New("orchestrator.gateway")
+	conn, err := dialOrchestrator(endpoint, log)
 	if err != nil {
 		return nil, err
 	}
-	return &GRPCGateway{client: rpc.NewOrchestratorServiceClient(conn), conn: conn, rpcTimeout: timeout}, nil
+
+	healthCtx, cancel := context.WithCancel(context.Background())
+	g := &GRPCGateway{
+		client:     rpc.NewOrchestratorServiceClient(conn),
+		conn:       conn,
+		rpcTimeout: timeout,
+		log:        log,
+		stopHealth: cancel,
+		healthDone: make(chan struct{}),
+	}
+	go g.watchHealth(healthCtx)
+	return g, nil
+}
+
+// watchHealth runs grpc_health_v1.Health.Watch in the background, tracking
+// SERVING/NOT_SERVING so Execute and FetchClusterState can fail fast
+// instead of hanging on a connection that's known to be down. It
+// reconnects the watch stream with a short backoff whenever it breaks.
+func (g *GRPCGateway) watchHealth(ctx context.Context) {
+	defer close(g.healthDone)
+	healthClient := grpc_health_v1.NewHealthClient(g.conn)
+	backoff := time.Second
+	const maxBackoff = 30 * time.Second
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		stream, err := healthClient.Watch(ctx, &grpc_health_v1.HealthCheckRequest{Service: orchestratorHealthService})
+		if err != nil {
+			g.healthy.Store(false)
+			if !sleepOrDone(ctx, backoff) {
+				return
+			}
+			backoff = nextBackoff(backoff, maxBackoff)
+			continue
+		}
+		backoff = time.Second
+
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				g.healthy.Store(false)
+				break
+			}
+			g.healthy.Store(resp.GetStatus() == grpc_health_v1.HealthCheckResponse_SERVING)
+		}
+
+		if !sleepOrDone(ctx, backoff) {
+			return
+		}
+	}
+}
+
+func sleepOrDone(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
 }
 
-// Close shuts down the underlying gRPC connection.
+func nextBackoff(cur, max time.Duration) time.Duration {
+	next := cur * 2
+	if next > max {
+		return max
+	}
+	return next
+}
+
+// Client returns the underlying rpc.OrchestratorServiceClient, letting
+// callers (e.g. the grpc-gateway reverse proxy mounted by
+// orchestrator/http) register the same generated REST handlers this
+// gateway already uses for /api/control and /api/status.
+func (g *GRPCGateway) Client() rpc.OrchestratorServiceClient {
+	if g == nil {
+		return nil
+	}
+	return g.client
+}
+
+// Healthy reports whether the background health checker last observed the
+// orchestrator as SERVING. It returns false until the first Watch response
+// arrives.
+func (g *GRPCGateway) Healthy() bool {
+	if g == nil {
+		return false
+	}
+	return g.healthy.Load()
+}
+
+// Close shuts down the health watcher and the underlying gRPC connection.
 func (g *GRPCGateway) Close() error {
 	if g == nil || g.conn == nil {
 		return nil
 	}
+	if g.stopHealth != nil {
+		g.stopHealth()
+		<-g.healthDone
+	}
 	return g.conn.Close()
 }
 
@@ -89,19 +235,52 @@ func (g *GRPCGateway) FetchClusterState(ctx context.Context) (*rpc.ClusterStateR
 	if g == nil {
 		return nil, errors.New("grpc gateway not initialised")
 	}
+	if !g.Healthy() {
+		return nil, ErrGatewayUnhealthy
+	}
 	ctx, cancel := context.WithTimeout(ctx, g.rpcTimeout)
 	defer cancel()
 	return g.client.GetClusterState(ctx, &rpc.ClusterStateRequest{})
 }
 
-// Execute forwards control commands to the orchestrator gRPC service.
-func (g *GRPCGateway) Execute(ctx context.Context, req ControlRequest) error {
+// WatchClusterState opens a server-streaming RPC that delivers a
+// ClusterStateResponse each time cluster membership or worker telemetry
+// changes. Unlike FetchClusterState it is not bounded by g.rpcTimeout:
+// the stream is meant to live as long as the caller (typically a
+// WebSocket bridge) wants updates, so the caller's ctx is the only
+// lifetime control.
+func (g *GRPCGateway) WatchClusterState(ctx context.Context) (rpc.OrchestratorService_WatchClusterStateClient, error) {
+	if g == nil {
+		return nil, errors.New("grpc gateway not initialised")
+	}
+	if !g.Healthy() {
+		return nil, ErrGatewayUnhealthy
+	}
+	return g.client.WatchClusterState(ctx, &rpc.ClusterStateRequest{})
+}
+
+// Execute forwards control commands to the orchestrator gRPC service. Each
+// call is logged with the command, worker_id, and role fields so operators
+// can correlate an HTTP /api/control request with the gRPC call it drove.
+func (g *GRPCGateway) Execute(ctx context.Context, req ControlRequest) (err error) {
 	if g == nil {
 		return errors.New("grpc gateway not initialised")
 	}
+	if !g.Healthy() {
+		return ErrGatewayUnhealthy
+	}
 	ctx, cancel := context.WithTimeout(ctx, g.rpcTimeout)
 	defer cancel()
 
+	log := g.log.With(logging.FieldCommand, req.Command, logging.FieldWorkerID, req.WorkerID, logging.FieldRole, req.Role)
+	defer func() {
+		if err != nil {
+			log.Error("execute failed", "error", err)
+			return
+		}
+		log.Info("execute ok")
+	}()
+
 	switch req.Command {
 	case "assign-role":
 		if req.WorkerID == "" || req.Role == "" {
@@ -130,7 +309,12 @@ func (g *GRPCGateway) Execute(ctx context.Context, req ControlRequest) error {
 	}
 }
 
-func dialOrchestrator(ctx context.Context, endpoint string) (*grpc.ClientConn, error) {
+// dialOrchestrator dials lazily: it returns as soon as the ClientConn is
+// constructed, without blocking for the connection to come up (no
+// grpc.WithBlock). watchHealth reports when the connection actually becomes
+// usable, and the configured backoff/retry policy handles reconnection
+// after a transient orchestrator restart.
+func dialOrchestrator(endpoint string, log logging.Logger) (*grpc.ClientConn, error) {
 	if strings.TrimSpace(endpoint) == "" {
 		endpoint = defaultOrchestratorEndpoint
 	}
@@ -154,7 +338,15 @@ func dialOrchestrator(ctx context.Context, endpoint string) (*grpc.ClientConn, e
 		return nil, err
 	}
 
-	return grpc.DialContext(ctx, hostPort, grpc.WithTransportCredentials(creds), grpc.WithBlock())
+	connectParams := grpc.ConnectParams{
+		Backoff: backoff.DefaultConfig,
+	}
+	return grpc.NewClient(hostPort,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithConnectParams(connectParams),
+		grpc.WithDefaultServiceConfig(defaultServiceConfig),
+		grpc.WithChainUnaryInterceptor(logging.UnaryClientInterceptor(log.Named("rpc"))),
+	)
 }
 
 func buildTLSCredentials(serverName string) (credentials.TransportCredentials, error) {
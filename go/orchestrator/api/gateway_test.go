@@ -1,16 +1,18 @@
 // CLASSIFICATION: COMMUNITY
-// Filename: gateway_test.go v0.1
+// Filename: gateway_test.go v0.2
 // Author: Lukas Bower
-// Date Modified: 2029-02-21
+// Date Modified: 2026-07-26
 // License: SPDX-License-Identifier: MIT OR Apache-2.0
 
 package api
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"cohesix/internal/logging"
 	"cohesix/internal/orchestrator/rpc"
 	"google.golang.org/grpc"
 )
@@ -53,11 +55,21 @@ func (f *fakeOrchestratorClient) GetClusterState(context.Context, *rpc.ClusterSt
 	return f.clusterStateResp, nil
 }
 
+func (f *fakeOrchestratorClient) WatchClusterState(context.Context, *rpc.ClusterStateRequest, ...grpc.CallOption) (rpc.OrchestratorService_WatchClusterStateClient, error) {
+	return nil, errors.New("WatchClusterState not implemented by fakeOrchestratorClient")
+}
+
 func (f *fakeOrchestratorClient) Close() error { return nil }
 
+func healthyGateway(client rpc.OrchestratorServiceClient) *GRPCGateway {
+	g := &GRPCGateway{client: client, rpcTimeout: time.Second, log: logging.New("test")}
+	g.healthy.Store(true)
+	return g
+}
+
 func TestGRPCGatewayExecutesAssignRole(t *testing.T) {
 	client := &fakeOrchestratorClient{}
-	gateway := &GRPCGateway{client: client, rpcTimeout: time.Second}
+	gateway := healthyGateway(client)
 	req := ControlRequest{Command: "assign-role", WorkerID: "worker-a", Role: "QueenPrimary"}
 	if err := gateway.Execute(context.Background(), req); err != nil {
 		t.Fatalf("execute: %v", err)
@@ -72,7 +84,7 @@ func TestGRPCGatewayExecutesAssignRole(t *testing.T) {
 
 func TestGRPCGatewayExecutesUpdateTrust(t *testing.T) {
 	client := &fakeOrchestratorClient{}
-	gateway := &GRPCGateway{client: client, rpcTimeout: time.Second}
+	gateway := healthyGateway(client)
 	req := ControlRequest{Command: "update-trust", WorkerID: "worker-a", TrustLevel: "amber"}
 	if err := gateway.Execute(context.Background(), req); err != nil {
 		t.Fatalf("execute: %v", err)
@@ -87,7 +99,7 @@ func TestGRPCGatewayExecutesUpdateTrust(t *testing.T) {
 
 func TestGRPCGatewayExecutesSchedule(t *testing.T) {
 	client := &fakeOrchestratorClient{}
-	gateway := &GRPCGateway{client: client, rpcTimeout: time.Second}
+	gateway := healthyGateway(client)
 	requireGPU := true
 	req := ControlRequest{Command: "schedule", AgentID: "cohrun-test", RequireGPU: &requireGPU}
 	if err := gateway.Execute(context.Background(), req); err != nil {
@@ -103,9 +115,20 @@ func TestGRPCGatewayExecutesSchedule(t *testing.T) {
 
 func TestGRPCGatewayRejectsUnknownCommand(t *testing.T) {
 	client := &fakeOrchestratorClient{}
-	gateway := &GRPCGateway{client: client, rpcTimeout: time.Second}
+	gateway := healthyGateway(client)
 	err := gateway.Execute(context.Background(), ControlRequest{Command: "noop"})
 	if err == nil {
 		t.Fatalf("expected error for unsupported command")
 	}
 }
+
+func TestGRPCGatewayWatchClusterStateRejectsWhenUnhealthy(t *testing.T) {
+	client := &fakeOrchestratorClient{}
+	gateway := &GRPCGateway{client: client, rpcTimeout: time.Second, log: logging.New("test")}
+	gateway.healthy.Store(false)
+
+	_, err := gateway.WatchClusterState(context.Background())
+	if !errors.Is(err, ErrGatewayUnhealthy) {
+		t.Fatalf("expected ErrGatewayUnhealthy, got %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: identity.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-25
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package api
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// IdentityBinding maps one workload identity to the Cohesix role it is
+// permitted to act as. Identities are matched by SPIFFE URI SAN path
+// first; SubjectDN is a fallback for certificates issued without a
+// spiffe:// SAN. It is loaded from the same creds JSON that already
+// carries HTTP Basic credentials and TLS file paths, so operators manage
+// both auth modes from a single file.
+type IdentityBinding struct {
+	SPIFFEPath string `json:"spiffe_path,omitempty"`
+	SubjectDN  string `json:"subject_dn,omitempty"`
+	Role       string `json:"role"`
+}
+
+// ErrUntrustedDomain signals that a client certificate's SPIFFE trust
+// domain is not in the configured allow-list.
+var ErrUntrustedDomain = errors.New("untrusted spiffe trust domain")
+
+// ErrNoIdentityBinding signals that a verified client certificate carries
+// no SPIFFE URI SAN or subject DN matching any configured IdentityBinding.
+var ErrNoIdentityBinding = errors.New("no matching identity binding")
+
+// IdentityResolver maps verified client certificates to Cohesix roles.
+type IdentityResolver struct {
+	trustDomains map[string]struct{}
+	byPath       map[string]string
+	bySubject    map[string]string
+}
+
+// NewIdentityResolver builds a resolver from a binding table and the set
+// of SPIFFE trust domains ("cohesix.internal", not "spiffe://cohesix.internal")
+// permitted to authenticate. An empty trustDomains trusts any domain;
+// deployments that mint their own CA per domain should always set it.
+func NewIdentityResolver(bindings []IdentityBinding, trustDomains []string) *IdentityResolver {
+	r := &IdentityResolver{
+		byPath:    make(map[string]string, len(bindings)),
+		bySubject: make(map[string]string, len(bindings)),
+	}
+	if len(trustDomains) > 0 {
+		r.trustDomains = make(map[string]struct{}, len(trustDomains))
+		for _, domain := range trustDomains {
+			domain = strings.TrimSpace(domain)
+			if domain != "" {
+				r.trustDomains[domain] = struct{}{}
+			}
+		}
+	}
+	for _, b := range bindings {
+		switch {
+		case b.SPIFFEPath != "":
+			r.byPath[b.SPIFFEPath] = b.Role
+		case b.SubjectDN != "":
+			r.bySubject[b.SubjectDN] = b.Role
+		}
+	}
+	return r
+}
+
+// Resolve extracts the caller's identity from a verified client
+// certificate and returns the Cohesix role it is bound to. The trust
+// domain of a spiffe:// SAN is checked before the binding table is
+// consulted, so a certificate from an unrecognized domain is refused
+// before any role lookup happens.
+func (r *IdentityResolver) Resolve(cert *x509.Certificate) (string, error) {
+	if cert == nil {
+		return "", ErrNoIdentityBinding
+	}
+	if path, domain, ok := spiffeURI(cert); ok {
+		if r.trustDomains != nil {
+			if _, trusted := r.trustDomains[domain]; !trusted {
+				return "", fmt.Errorf("%w: %s", ErrUntrustedDomain, domain)
+			}
+		}
+		if role, ok := r.byPath[path]; ok {
+			return role, nil
+		}
+	}
+	if role, ok := r.bySubject[cert.Subject.String()]; ok {
+		return role, nil
+	}
+	return "", ErrNoIdentityBinding
+}
+
+// spiffeURI returns the path (e.g. "/ns/prod/sa/queen") and trust domain
+// (e.g. "cohesix.internal") of the first spiffe:// URI SAN on cert, if
+// any.
+func spiffeURI(cert *x509.Certificate) (path, domain string, ok bool) {
+	for _, u := range cert.URIs {
+		if u.Scheme != "spiffe" {
+			continue
+		}
+		return u.Path, u.Host, true
+	}
+	return "", "", false
+}
@@ -0,0 +1,81 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: identity_test.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-25
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package api
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func certWithSPIFFE(rawURI string) *x509.Certificate {
+	u, err := url.Parse(rawURI)
+	if err != nil {
+		panic(err)
+	}
+	return &x509.Certificate{URIs: []*url.URL{u}}
+}
+
+func TestIdentityResolverResolvesBySPIFFEPath(t *testing.T) {
+	resolver := NewIdentityResolver([]IdentityBinding{
+		{SPIFFEPath: "/ns/prod/sa/queen", Role: "QueenPrimary"},
+	}, []string{"cohesix.internal"})
+
+	role, err := resolver.Resolve(certWithSPIFFE("spiffe://cohesix.internal/ns/prod/sa/queen"))
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if role != "QueenPrimary" {
+		t.Fatalf("unexpected role: %s", role)
+	}
+}
+
+func TestIdentityResolverRejectsUntrustedDomain(t *testing.T) {
+	resolver := NewIdentityResolver([]IdentityBinding{
+		{SPIFFEPath: "/ns/prod/sa/queen", Role: "QueenPrimary"},
+	}, []string{"cohesix.internal"})
+
+	_, err := resolver.Resolve(certWithSPIFFE("spiffe://evil.example/ns/prod/sa/queen"))
+	if !errors.Is(err, ErrUntrustedDomain) {
+		t.Fatalf("expected ErrUntrustedDomain, got %v", err)
+	}
+}
+
+func TestIdentityResolverRejectsUnboundPath(t *testing.T) {
+	resolver := NewIdentityResolver([]IdentityBinding{
+		{SPIFFEPath: "/ns/prod/sa/queen", Role: "QueenPrimary"},
+	}, nil)
+
+	_, err := resolver.Resolve(certWithSPIFFE("spiffe://cohesix.internal/ns/prod/sa/unknown"))
+	if !errors.Is(err, ErrNoIdentityBinding) {
+		t.Fatalf("expected ErrNoIdentityBinding, got %v", err)
+	}
+}
+
+func TestIdentityResolverFallsBackToSubjectDN(t *testing.T) {
+	resolver := NewIdentityResolver([]IdentityBinding{
+		{SubjectDN: "CN=queen-primary,O=Cohesix", Role: "QueenPrimary"},
+	}, nil)
+
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "queen-primary", Organization: []string{"Cohesix"}}}
+	role, err := resolver.Resolve(cert)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if role != "QueenPrimary" {
+		t.Fatalf("unexpected role: %s", role)
+	}
+}
+
+func TestIdentityResolverNilCertificate(t *testing.T) {
+	resolver := NewIdentityResolver(nil, nil)
+	if _, err := resolver.Resolve(nil); !errors.Is(err, ErrNoIdentityBinding) {
+		t.Fatalf("expected ErrNoIdentityBinding, got %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: machines.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MachineIdentity records one worker that self-enrolled through
+// /api/enroll, pending an operator's approval via a `srvctl machines
+// validate` call. Roles are the roles the enrollment token granted, not
+// roles the worker chose for itself.
+type MachineIdentity struct {
+	MachineID       string    `json:"machine_id"`
+	Roles           []string  `json:"roles"`
+	CertFingerprint string    `json:"cert_fingerprint"`
+	EnrolledAt      time.Time `json:"enrolled_at"`
+	Validated       bool      `json:"validated"`
+}
+
+// ErrMachineNotFound signals that a machine_id has no enrollment record.
+var ErrMachineNotFound = errors.New("machine not found")
+
+// MachineRegistry persists enrolled machine identities to a JSON file,
+// typically /srv/orch_machines.json -- the self-enrollment analogue of the
+// pre-provisioned credentials in /srv/orch_user.json. Every Enroll,
+// Validate, and Delete call rewrites the file in full, trading write
+// amplification for simplicity at this record count.
+type MachineRegistry struct {
+	path string
+
+	mu       sync.Mutex
+	machines map[string]MachineIdentity
+}
+
+// NewMachineRegistry loads path if it exists, or starts empty -- the first
+// worker to enroll creates the file.
+func NewMachineRegistry(path string) (*MachineRegistry, error) {
+	r := &MachineRegistry{path: path, machines: make(map[string]MachineIdentity)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read machine registry: %w", err)
+	}
+	var machines []MachineIdentity
+	if err := json.Unmarshal(data, &machines); err != nil {
+		return nil, fmt.Errorf("parse machine registry: %w", err)
+	}
+	for _, m := range machines {
+		r.machines[m.MachineID] = m
+	}
+	return r, nil
+}
+
+// Enroll records a newly self-enrolled machine as unvalidated.
+func (r *MachineRegistry) Enroll(m MachineIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.machines[m.MachineID] = m
+	return r.save()
+}
+
+// Get looks up one enrolled machine by ID.
+func (r *MachineRegistry) Get(machineID string) (MachineIdentity, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m, ok := r.machines[machineID]
+	return m, ok
+}
+
+// List returns every enrolled machine, sorted by machine_id.
+func (r *MachineRegistry) List() []MachineIdentity {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.sortedLocked()
+}
+
+// Validate marks machineID as operator-approved, letting it pass
+// ValidatingController's gate.
+func (r *MachineRegistry) Validate(machineID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m, ok := r.machines[machineID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrMachineNotFound, machineID)
+	}
+	m.Validated = true
+	r.machines[machineID] = m
+	return r.save()
+}
+
+// Delete removes machineID from the registry entirely, e.g. after
+// decommissioning a worker.
+func (r *MachineRegistry) Delete(machineID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.machines[machineID]; !ok {
+		return fmt.Errorf("%w: %s", ErrMachineNotFound, machineID)
+	}
+	delete(r.machines, machineID)
+	return r.save()
+}
+
+// sortedLocked must be called with r.mu held.
+func (r *MachineRegistry) sortedLocked() []MachineIdentity {
+	out := make([]MachineIdentity, 0, len(r.machines))
+	for _, m := range r.machines {
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].MachineID < out[j].MachineID })
+	return out
+}
+
+// save must be called with r.mu held.
+func (r *MachineRegistry) save() error {
+	data, err := json.MarshalIndent(r.sortedLocked(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, data, 0o600)
+}
+
+// machineIDKey is the context key WithMachineID/MachineIDFromContext use,
+// unexported like accesslog's context keys so only this package's helpers
+// can set or read it.
+type machineIDKey struct{}
+
+// WithMachineID attaches the enrolled machine_id a request's client
+// certificate fingerprint resolved to, for ValidatingController to gate on
+// downstream.
+func WithMachineID(ctx context.Context, machineID string) context.Context {
+	return context.WithValue(ctx, machineIDKey{}, machineID)
+}
+
+// MachineIDFromContext returns the machine_id WithMachineID attached to
+// ctx, if any.
+func MachineIDFromContext(ctx context.Context) (string, bool) {
+	machineID, ok := ctx.Value(machineIDKey{}).(string)
+	return machineID, ok
+}
+
+// ErrMachineNotValidated signals that a self-enrolled machine has not yet
+// been approved by an operator.
+var ErrMachineNotValidated = errors.New("machine is not validated")
+
+// ValidatingController wraps a Controller so that, when ctx carries a
+// machine_id (see WithMachineID), Execute is refused unless the registry
+// has approved that machine. A request with no machine_id attached -- e.g.
+// one authenticated by Basic auth or a pre-provisioned identity binding
+// rather than a self-enrolled certificate -- passes through unchanged:
+// gating only applies to the self-enrollment path this registry covers.
+type ValidatingController struct {
+	Controller Controller
+	Registry   *MachineRegistry
+}
+
+// Execute implements Controller.
+func (c *ValidatingController) Execute(ctx context.Context, cmd ControlRequest) error {
+	if machineID, ok := MachineIDFromContext(ctx); ok {
+		machine, found := c.Registry.Get(machineID)
+		if !found || !machine.Validated {
+			return ErrMachineNotValidated
+		}
+	}
+	return c.Controller.Execute(ctx, cmd)
+}
@@ -0,0 +1,150 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: machines_test.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package api
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestMachineRegistryEnrollAndGet(t *testing.T) {
+	registry, err := NewMachineRegistry(filepath.Join(t.TempDir(), "machines.json"))
+	if err != nil {
+		t.Fatalf("new registry: %v", err)
+	}
+	if err := registry.Enroll(MachineIdentity{MachineID: "worker-1", Roles: []string{"DroneWorker"}}); err != nil {
+		t.Fatalf("enroll: %v", err)
+	}
+	machine, ok := registry.Get("worker-1")
+	if !ok {
+		t.Fatal("expected machine to be found")
+	}
+	if machine.Validated {
+		t.Fatal("expected newly enrolled machine to be unvalidated")
+	}
+}
+
+func TestMachineRegistryPersistsAcrossReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "machines.json")
+	registry, err := NewMachineRegistry(path)
+	if err != nil {
+		t.Fatalf("new registry: %v", err)
+	}
+	if err := registry.Enroll(MachineIdentity{MachineID: "worker-1", Roles: []string{"DroneWorker"}}); err != nil {
+		t.Fatalf("enroll: %v", err)
+	}
+
+	reloaded, err := NewMachineRegistry(path)
+	if err != nil {
+		t.Fatalf("reload registry: %v", err)
+	}
+	if _, ok := reloaded.Get("worker-1"); !ok {
+		t.Fatal("expected machine to survive reload")
+	}
+}
+
+func TestMachineRegistryValidate(t *testing.T) {
+	registry, err := NewMachineRegistry(filepath.Join(t.TempDir(), "machines.json"))
+	if err != nil {
+		t.Fatalf("new registry: %v", err)
+	}
+	if err := registry.Enroll(MachineIdentity{MachineID: "worker-1"}); err != nil {
+		t.Fatalf("enroll: %v", err)
+	}
+	if err := registry.Validate("worker-1"); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	machine, _ := registry.Get("worker-1")
+	if !machine.Validated {
+		t.Fatal("expected machine to be validated")
+	}
+}
+
+func TestMachineRegistryValidateUnknownMachine(t *testing.T) {
+	registry, err := NewMachineRegistry(filepath.Join(t.TempDir(), "machines.json"))
+	if err != nil {
+		t.Fatalf("new registry: %v", err)
+	}
+	if err := registry.Validate("ghost"); !errors.Is(err, ErrMachineNotFound) {
+		t.Fatalf("expected ErrMachineNotFound, got %v", err)
+	}
+}
+
+func TestMachineRegistryDelete(t *testing.T) {
+	registry, err := NewMachineRegistry(filepath.Join(t.TempDir(), "machines.json"))
+	if err != nil {
+		t.Fatalf("new registry: %v", err)
+	}
+	if err := registry.Enroll(MachineIdentity{MachineID: "worker-1"}); err != nil {
+		t.Fatalf("enroll: %v", err)
+	}
+	if err := registry.Delete("worker-1"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, ok := registry.Get("worker-1"); ok {
+		t.Fatal("expected machine to be gone")
+	}
+}
+
+func TestMachineRegistryListSortedByMachineID(t *testing.T) {
+	registry, err := NewMachineRegistry(filepath.Join(t.TempDir(), "machines.json"))
+	if err != nil {
+		t.Fatalf("new registry: %v", err)
+	}
+	registry.Enroll(MachineIdentity{MachineID: "worker-b"})
+	registry.Enroll(MachineIdentity{MachineID: "worker-a"})
+	list := registry.List()
+	if len(list) != 2 || list[0].MachineID != "worker-a" || list[1].MachineID != "worker-b" {
+		t.Fatalf("expected sorted list, got %+v", list)
+	}
+}
+
+func TestValidatingControllerRejectsUnvalidatedMachine(t *testing.T) {
+	registry, err := NewMachineRegistry(filepath.Join(t.TempDir(), "machines.json"))
+	if err != nil {
+		t.Fatalf("new registry: %v", err)
+	}
+	if err := registry.Enroll(MachineIdentity{MachineID: "worker-1"}); err != nil {
+		t.Fatalf("enroll: %v", err)
+	}
+	controller := &ValidatingController{Controller: noopController{}, Registry: registry}
+	ctx := WithMachineID(context.Background(), "worker-1")
+	if err := controller.Execute(ctx, ControlRequest{}); !errors.Is(err, ErrMachineNotValidated) {
+		t.Fatalf("expected ErrMachineNotValidated, got %v", err)
+	}
+}
+
+func TestValidatingControllerAllowsValidatedMachine(t *testing.T) {
+	registry, err := NewMachineRegistry(filepath.Join(t.TempDir(), "machines.json"))
+	if err != nil {
+		t.Fatalf("new registry: %v", err)
+	}
+	if err := registry.Enroll(MachineIdentity{MachineID: "worker-1"}); err != nil {
+		t.Fatalf("enroll: %v", err)
+	}
+	if err := registry.Validate("worker-1"); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	controller := &ValidatingController{Controller: noopController{}, Registry: registry}
+	ctx := WithMachineID(context.Background(), "worker-1")
+	if err := controller.Execute(ctx, ControlRequest{}); err != nil {
+		t.Fatalf("expected Execute to pass through, got %v", err)
+	}
+}
+
+func TestValidatingControllerPassesThroughWithoutMachineID(t *testing.T) {
+	registry, err := NewMachineRegistry(filepath.Join(t.TempDir(), "machines.json"))
+	if err != nil {
+		t.Fatalf("new registry: %v", err)
+	}
+	controller := &ValidatingController{Controller: noopController{}, Registry: registry}
+	if err := controller.Execute(context.Background(), ControlRequest{}); err != nil {
+		t.Fatalf("expected Execute to pass through for non-machine callers, got %v", err)
+	}
+}
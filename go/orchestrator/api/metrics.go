@@ -0,0 +1,153 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: metrics.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"cohesix/internal/logging"
+)
+
+var metricsLog = logging.New("orchestrator.telemetry")
+
+// namespace matches the "cohesix" prefix orchestrator/metrics.Registry
+// already uses, so both collectors' series live in one recognisable family
+// when scraped together.
+const namespace = "cohesix"
+
+// MetricsExporter mirrors one StatusResponse snapshot -- the same one
+// MetricsRegistry turns into Prometheus gauges -- to an external sink, so a
+// deployer can push the same worker/GPU telemetry as OTLP without
+// MetricsRegistry itself depending on any one backend. Export errors are
+// logged and otherwise ignored: a failing push must never break the
+// Prometheus scrape that shares this snapshot.
+type MetricsExporter interface {
+	Export(ctx context.Context, resp StatusResponse) error
+}
+
+// MetricsRegistry is a prometheus.Collector exposing worker and GPU
+// telemetry -- derived from the same ClusterStateClient snapshot that feeds
+// Status -- as gauges. Unlike metrics.Registry's fixed CounterVec/GaugeVec
+// collectors, the values here are computed fresh on every Collect call
+// rather than kept as long-lived state: the worker/GPU label set changes as
+// workers join and leave the cluster, which the Collector pattern handles
+// naturally where a persistent GaugeVec would accumulate stale series for
+// workers that are long gone.
+type MetricsRegistry struct {
+	client    ClusterStateClient
+	exporters []MetricsExporter
+
+	workerUp       *prometheus.Desc
+	gpuMemFree     *prometheus.Desc
+	gpuCurrentLoad *prometheus.Desc
+	gpuTempCelsius *prometheus.Desc
+	gpuLatency     *prometheus.Desc
+	gpuPerfWatt    *prometheus.Desc
+
+	fetchErrors  prometheus.Counter
+	fetchLatency prometheus.Histogram
+}
+
+// NewMetricsRegistry returns a MetricsRegistry reading worker/GPU state from
+// client on every scrape. Each snapshot Collect fetches is also handed to
+// exporters, if any -- see MetricsExporter.
+func NewMetricsRegistry(client ClusterStateClient, exporters ...MetricsExporter) *MetricsRegistry {
+	return &MetricsRegistry{
+		client:    client,
+		exporters: exporters,
+		workerUp: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "worker_up"),
+			"Whether a worker is present in the current cluster state (always 1; a worker's absence from the scrape means it is gone).",
+			[]string{"worker_id", "role", "trust"}, nil,
+		),
+		gpuMemFree: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "gpu", "mem_free_bytes"),
+			"Free GPU memory in bytes, by worker.",
+			[]string{"worker_id"}, nil,
+		),
+		gpuCurrentLoad: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "gpu", "current_load"),
+			"Current GPU load reported by the worker.",
+			[]string{"worker_id"}, nil,
+		),
+		gpuTempCelsius: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "gpu", "temp_celsius"),
+			"Last reported GPU temperature in Celsius, by worker.",
+			[]string{"worker_id"}, nil,
+		),
+		gpuLatency: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "gpu", "latency_score"),
+			"GPU latency score reported by the worker.",
+			[]string{"worker_id"}, nil,
+		),
+		gpuPerfWatt: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "gpu", "perf_watt"),
+			"GPU performance-per-watt reported by the worker.",
+			[]string{"worker_id"}, nil,
+		),
+		fetchErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "orchestrator_rpc_errors_total",
+			Help:      "Total FetchClusterState RPC errors encountered while collecting worker/GPU telemetry.",
+		}),
+		fetchLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "orchestrator_state_fetch_duration_seconds",
+			Help:      "FetchClusterState latency in seconds while collecting worker/GPU telemetry.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (r *MetricsRegistry) Describe(ch chan<- *prometheus.Desc) {
+	ch <- r.workerUp
+	ch <- r.gpuMemFree
+	ch <- r.gpuCurrentLoad
+	ch <- r.gpuTempCelsius
+	ch <- r.gpuLatency
+	ch <- r.gpuPerfWatt
+	r.fetchErrors.Describe(ch)
+	r.fetchLatency.Describe(ch)
+}
+
+// Collect implements prometheus.Collector, fetching the current cluster
+// state and emitting one worker_up sample (plus gpu_* samples, if the
+// worker reports GPU telemetry) per worker.
+func (r *MetricsRegistry) Collect(ch chan<- prometheus.Metric) {
+	start := time.Now()
+	state, err := r.client.FetchClusterState(context.Background())
+	r.fetchLatency.Observe(time.Since(start).Seconds())
+	r.fetchLatency.Collect(ch)
+	r.fetchErrors.Collect(ch)
+	if err != nil {
+		r.fetchErrors.Inc()
+		return
+	}
+
+	resp := BuildStatusResponse(state)
+	for _, worker := range resp.WorkerStatuses {
+		ch <- prometheus.MustNewConstMetric(r.workerUp, prometheus.GaugeValue, 1, worker.WorkerID, worker.Role, worker.Trust)
+		if worker.GPU == nil {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(r.gpuMemFree, prometheus.GaugeValue, float64(worker.GPU.MemFree), worker.WorkerID)
+		ch <- prometheus.MustNewConstMetric(r.gpuCurrentLoad, prometheus.GaugeValue, float64(worker.GPU.CurrentLoad), worker.WorkerID)
+		ch <- prometheus.MustNewConstMetric(r.gpuTempCelsius, prometheus.GaugeValue, float64(worker.GPU.LastTemp), worker.WorkerID)
+		ch <- prometheus.MustNewConstMetric(r.gpuLatency, prometheus.GaugeValue, float64(worker.GPU.LatencyScore), worker.WorkerID)
+		ch <- prometheus.MustNewConstMetric(r.gpuPerfWatt, prometheus.GaugeValue, float64(worker.GPU.PerfWatt), worker.WorkerID)
+	}
+
+	for _, exporter := range r.exporters {
+		if exportErr := exporter.Export(context.Background(), resp); exportErr != nil {
+			metricsLog.Warn("metrics export failed", "error", exportErr)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: metrics_test.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package api
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"cohesix/internal/orchestrator/rpc"
+)
+
+func TestMetricsRegistryCollectsWorkerAndGPUGauges(t *testing.T) {
+	client := &sequencedClusterStateClient{states: []*rpc.ClusterStateResponse{{
+		QueenId: "queen-a",
+		Workers: []*rpc.WorkerState{
+			{WorkerId: "worker-a", Role: "DroneWorker", Trust: "trusted"},
+			{WorkerId: "worker-b", Role: "DroneWorker", Trust: "trusted", Gpu: &rpc.GpuTelemetry{MemFree: 1024, CurrentLoad: 42}},
+		},
+	}}}
+	reg := NewMetricsRegistry(client)
+
+	promReg := prometheus.NewRegistry()
+	promReg.MustRegister(reg)
+	families, err := promReg.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+
+	byName := map[string]bool{}
+	for _, fam := range families {
+		byName[fam.GetName()] = true
+	}
+	for _, name := range []string{
+		"cohesix_worker_up",
+		"cohesix_gpu_mem_free_bytes",
+		"cohesix_gpu_current_load",
+		"cohesix_orchestrator_rpc_errors_total",
+		"cohesix_orchestrator_state_fetch_duration_seconds",
+	} {
+		if !byName[name] {
+			t.Errorf("expected metric family %q, got families: %v", name, byName)
+		}
+	}
+
+	if got := testutil.ToFloat64(reg.fetchErrors); got != 0 {
+		t.Fatalf("expected no fetch errors, got %v", got)
+	}
+}
+
+type erroringClusterStateClient struct{}
+
+func (erroringClusterStateClient) FetchClusterState(ctx context.Context) (*rpc.ClusterStateResponse, error) {
+	return nil, errors.New("unavailable")
+}
+
+func TestMetricsRegistryCountsFetchErrors(t *testing.T) {
+	reg := NewMetricsRegistry(erroringClusterStateClient{})
+	promReg := prometheus.NewRegistry()
+	promReg.MustRegister(reg)
+
+	if _, err := promReg.Gather(); err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+	if got := testutil.ToFloat64(reg.fetchErrors); got != 1 {
+		t.Fatalf("expected one fetch error, got %v", got)
+	}
+}
+
+type recordingExporter struct {
+	calls int
+	err   error
+}
+
+func (e *recordingExporter) Export(ctx context.Context, resp StatusResponse) error {
+	e.calls++
+	return e.err
+}
+
+func TestMetricsRegistryInvokesExporters(t *testing.T) {
+	client := &sequencedClusterStateClient{states: []*rpc.ClusterStateResponse{{QueenId: "queen-a"}}}
+	exporter := &recordingExporter{}
+	reg := NewMetricsRegistry(client, exporter)
+
+	promReg := prometheus.NewRegistry()
+	promReg.MustRegister(reg)
+	if _, err := promReg.Gather(); err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+
+	if exporter.calls != 1 {
+		t.Fatalf("expected exporter to be called once, got %d", exporter.calls)
+	}
+}
+
+func TestMetricsRegistryExporterErrorDoesNotFailCollect(t *testing.T) {
+	client := &sequencedClusterStateClient{states: []*rpc.ClusterStateResponse{{QueenId: "queen-a"}}}
+	exporter := &recordingExporter{err: errors.New("push failed")}
+	reg := NewMetricsRegistry(client, exporter)
+
+	promReg := prometheus.NewRegistry()
+	promReg.MustRegister(reg)
+	families, err := promReg.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+	if len(families) == 0 {
+		t.Fatal("expected metric families despite exporter error")
+	}
+}
+
+func TestMetricsRegistryDescribesRequestedMetricNames(t *testing.T) {
+	reg := NewMetricsRegistry(&sequencedClusterStateClient{states: []*rpc.ClusterStateResponse{{}}})
+	descCh := make(chan *prometheus.Desc, 16)
+	reg.Describe(descCh)
+	close(descCh)
+
+	var rendered []string
+	for desc := range descCh {
+		rendered = append(rendered, desc.String())
+	}
+	for _, name := range []string{
+		"cohesix_worker_up", "cohesix_gpu_mem_free_bytes", "cohesix_gpu_current_load",
+		"cohesix_gpu_temp_celsius", "cohesix_gpu_latency_score", "cohesix_gpu_perf_watt",
+	} {
+		found := false
+		for _, r := range rendered {
+			if strings.Contains(r, name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("expected a Desc naming %q, got %v", name, rendered)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: rotating_client.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package api
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"cohesix/internal/orchestrator/rpc"
+)
+
+// RotatingClusterStateClient lets the ClusterStateClient behind it be
+// swapped at runtime -- e.g. when a SIGHUP-triggered config reload redials
+// the orchestrator gRPC target -- without disturbing callers that already
+// hold a reference to the RotatingClusterStateClient itself, such as a
+// route handler closure built once at startup. FetchClusterState always
+// dispatches to whichever delegate is current at call time, so requests
+// already in flight against the old delegate finish undisturbed while new
+// ones pick up the swap.
+type RotatingClusterStateClient struct {
+	delegate atomic.Pointer[ClusterStateClient]
+}
+
+// NewRotatingClusterStateClient returns a RotatingClusterStateClient that
+// starts out delegating to initial.
+func NewRotatingClusterStateClient(initial ClusterStateClient) *RotatingClusterStateClient {
+	r := &RotatingClusterStateClient{}
+	r.Store(initial)
+	return r
+}
+
+// Store swaps the delegate FetchClusterState calls reach from this point on.
+func (r *RotatingClusterStateClient) Store(client ClusterStateClient) {
+	r.delegate.Store(&client)
+}
+
+// Swap stores client as the new delegate and returns the previous one, nil
+// if none was set, so a caller can close the outgoing delegate once it is
+// safe to do so.
+func (r *RotatingClusterStateClient) Swap(client ClusterStateClient) ClusterStateClient {
+	prev := r.delegate.Swap(&client)
+	if prev == nil {
+		return nil
+	}
+	return *prev
+}
+
+// ErrClusterStateClientNotConfigured is returned by FetchClusterState before
+// Store/Swap has ever been called.
+var ErrClusterStateClientNotConfigured = errors.New("cluster state client not configured")
+
+// FetchClusterState implements ClusterStateClient by delegating to whichever
+// client was most recently stored.
+func (r *RotatingClusterStateClient) FetchClusterState(ctx context.Context) (*rpc.ClusterStateResponse, error) {
+	delegate := r.delegate.Load()
+	if delegate == nil || *delegate == nil {
+		return nil, ErrClusterStateClientNotConfigured
+	}
+	return (*delegate).FetchClusterState(ctx)
+}
@@ -0,0 +1,49 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: rotating_client_test.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package api
+
+import (
+	"context"
+	"testing"
+
+	"cohesix/internal/orchestrator/rpc"
+)
+
+func TestRotatingClusterStateClientDelegatesToCurrent(t *testing.T) {
+	first := &sequencedClusterStateClient{states: []*rpc.ClusterStateResponse{{QueenId: "queen-a"}}}
+	second := &sequencedClusterStateClient{states: []*rpc.ClusterStateResponse{{QueenId: "queen-b"}}}
+
+	rotating := NewRotatingClusterStateClient(first)
+	state, err := rotating.FetchClusterState(context.Background())
+	if err != nil || state.GetQueenId() != "queen-a" {
+		t.Fatalf("expected queen-a, got %+v (err %v)", state, err)
+	}
+
+	rotating.Store(second)
+	state, err = rotating.FetchClusterState(context.Background())
+	if err != nil || state.GetQueenId() != "queen-b" {
+		t.Fatalf("expected queen-b after Store, got %+v (err %v)", state, err)
+	}
+}
+
+func TestRotatingClusterStateClientSwapReturnsPrevious(t *testing.T) {
+	first := &sequencedClusterStateClient{states: []*rpc.ClusterStateResponse{{QueenId: "queen-a"}}}
+	second := &sequencedClusterStateClient{states: []*rpc.ClusterStateResponse{{QueenId: "queen-b"}}}
+
+	rotating := NewRotatingClusterStateClient(first)
+	prev := rotating.Swap(second)
+	if prev != ClusterStateClient(first) {
+		t.Fatalf("expected Swap to return the previous delegate")
+	}
+}
+
+func TestRotatingClusterStateClientFetchBeforeStoreErrors(t *testing.T) {
+	rotating := &RotatingClusterStateClient{}
+	if _, err := rotating.FetchClusterState(context.Background()); err != ErrClusterStateClientNotConfigured {
+		t.Fatalf("expected ErrClusterStateClientNotConfigured, got %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: schema.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package api
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// StatusSchemaJSON is the JSON Schema (draft-07) describing StatusResponse/
+// WorkerSummary/GPUSummary, served at /api/status/schema.json so SDK
+// generators and other out-of-tree consumers have a machine-readable
+// contract for what Status returns, instead of having to infer it from
+// hand-read struct tags.
+//
+//go:embed status.schema.json
+var StatusSchemaJSON []byte
+
+// SchemaHandler serves StatusSchemaJSON as-is.
+func SchemaHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.Write(StatusSchemaJSON)
+}
+
+// requiredStatusFields/requiredWorkerFields/requiredGPUFields mirror the
+// "required" arrays in status.schema.json. This repo has no JSON Schema
+// validator dependency (santhosh-tekuri/jsonschema or similar) in go.mod,
+// so rather than fabricate one, ValidateStatusSchemaSample hand-checks the
+// same required-field sets a real validator would enforce -- enough to
+// catch the common drift (a renamed or dropped json tag) in CI without a
+// new third-party dependency.
+var (
+	requiredStatusFields = []string{"status", "role", "queen_id", "workers", "generated_at", "timeout_seconds", "worker_statuses"}
+	requiredWorkerFields = []string{"worker_id", "role", "status", "ip", "trust", "boot_ts", "last_seen", "capabilities"}
+	requiredGPUFields    = []string{"perf_watt", "mem_total", "mem_free", "last_temp", "gpu_capacity", "current_load", "latency_score"}
+)
+
+// ValidateStatusSchemaSample encodes a representative StatusResponse with
+// jsonEncoder and checks the result against status.schema.json's required
+// fields, returning an error describing the first mismatch. Server.New
+// calls this at startup so a struct-tag rename that drifts from the
+// embedded schema fails fast instead of surfacing as a confusing SDK bug
+// downstream.
+func ValidateStatusSchemaSample() error {
+	sample := StatusResponse{
+		Status:         "ok",
+		Role:           "Queen",
+		QueenID:        "queen-sample",
+		Workers:        1,
+		GeneratedAt:    1,
+		TimeoutSeconds: 5,
+		WorkerStatuses: []WorkerSummary{
+			{
+				WorkerID:     "worker-sample",
+				Role:         "DroneWorker",
+				Status:       "ready",
+				IP:           "10.0.0.1",
+				Trust:        "green",
+				BootTS:       1,
+				LastSeen:     1,
+				Capabilities: []string{"cuda"},
+				GPU: &GPUSummary{
+					PerfWatt:     1,
+					MemTotal:     1,
+					MemFree:      1,
+					LastTemp:     1,
+					GPUCapacity:  1,
+					CurrentLoad:  1,
+					LatencyScore: 1,
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := (jsonEncoder{}).Encode(&buf, sample); err != nil {
+		return fmt.Errorf("encode schema sample: %w", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		return fmt.Errorf("decode schema sample: %w", err)
+	}
+	if err := requireFields(decoded, requiredStatusFields); err != nil {
+		return fmt.Errorf("status_response: %w", err)
+	}
+
+	workers, _ := decoded["worker_statuses"].([]any)
+	if len(workers) == 0 {
+		return fmt.Errorf("status_response: worker_statuses: expected at least one sample worker")
+	}
+	worker, ok := workers[0].(map[string]any)
+	if !ok {
+		return fmt.Errorf("status_response: worker_statuses[0]: not an object")
+	}
+	if err := requireFields(worker, requiredWorkerFields); err != nil {
+		return fmt.Errorf("worker_summary: %w", err)
+	}
+
+	gpu, ok := worker["gpu"].(map[string]any)
+	if !ok {
+		return fmt.Errorf("worker_summary: gpu: not an object")
+	}
+	if err := requireFields(gpu, requiredGPUFields); err != nil {
+		return fmt.Errorf("gpu_summary: %w", err)
+	}
+	return nil
+}
+
+func requireFields(obj map[string]any, fields []string) error {
+	for _, f := range fields {
+		if _, ok := obj[f]; !ok {
+			return fmt.Errorf("missing required field %q", f)
+		}
+	}
+	return nil
+}
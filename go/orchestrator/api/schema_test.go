@@ -0,0 +1,45 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: schema_test.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestValidateStatusSchemaSamplePasses(t *testing.T) {
+	if err := ValidateStatusSchemaSample(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRequireFieldsReportsFirstMissingField(t *testing.T) {
+	obj := map[string]any{"status": "ok"}
+	err := requireFields(obj, requiredStatusFields)
+	if err == nil {
+		t.Fatal("expected an error for missing fields")
+	}
+}
+
+func TestSchemaHandlerServesEmbeddedSchema(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/status/schema.json", nil)
+	rec := httptest.NewRecorder()
+
+	SchemaHandler(rec, req)
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/schema+json" {
+		t.Fatalf("unexpected Content-Type: %q", ct)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &schema); err != nil {
+		t.Fatalf("decode schema: %v", err)
+	}
+	if schema["title"] != "StatusResponse" {
+		t.Fatalf("unexpected schema title: %v", schema["title"])
+	}
+}
@@ -1,20 +1,39 @@
 // CLASSIFICATION: COMMUNITY
-// Filename: status.go v0.1
+// Filename: status.go v0.2
 // Author: Lukas Bower
-// Date Modified: 2029-02-15
+// Date Modified: 2026-07-26
 // License: SPDX-License-Identifier: MIT OR Apache-2.0
 
 package api
 
 import (
+	"compress/gzip"
+	"encoding/binary"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
 	"net/http"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"cohesix/internal/orchestrator/rpc"
 )
 
+// fetchTimeoutResponse is the structured body Status returns when the
+// ClusterStateClient gives up before its deadline (see
+// DeadlineClusterStateClient), instead of the plain-text 502 any other
+// FetchClusterState failure gets.
+type fetchTimeoutResponse struct {
+	Error     string `json:"error"`
+	ElapsedMS int64  `json:"elapsed_ms"`
+}
+
 // StatusResponse describes orchestrator state.
 type StatusResponse struct {
 	Uptime         string          `json:"uptime"`
@@ -60,42 +79,144 @@ func Status(start time.Time, client ClusterStateClient) http.HandlerFunc {
 		}
 		state, err := client.FetchClusterState(r.Context())
 		if err != nil {
+			var timeoutErr *FetchTimeoutError
+			if errors.As(err, &timeoutErr) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusGatewayTimeout)
+				json.NewEncoder(w).Encode(fetchTimeoutResponse{
+					Error:     "fetch_timeout",
+					ElapsedMS: timeoutErr.Elapsed.Milliseconds(),
+				})
+				return
+			}
 			http.Error(w, err.Error(), http.StatusBadGateway)
 			return
 		}
 
-		var workers []WorkerSummary
-		for _, wkr := range state.GetWorkers() {
-			workers = append(workers, WorkerSummary{
-				WorkerID:     wkr.GetWorkerId(),
-				Role:         wkr.GetRole(),
-				Status:       wkr.GetStatus(),
-				IP:           wkr.GetIp(),
-				Trust:        wkr.GetTrust(),
-				BootTS:       wkr.GetBootTs(),
-				LastSeen:     wkr.GetLastSeen(),
-				Capabilities: append([]string(nil), wkr.GetCapabilities()...),
-				GPU:          convertGPU(wkr.GetGpu()),
-			})
-		}
+		resp := BuildStatusResponse(state)
+		resp.Uptime = time.Since(start).Round(time.Second).String()
 
-		role := os.Getenv("COHESIX_ROLE")
-		if role == "" {
-			role = "Queen"
+		etag := `"` + statusETag(resp) + `"`
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", resp.TimeoutSeconds/2))
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
 		}
-		resp := StatusResponse{
-			Uptime:         time.Since(start).Round(time.Second).String(),
-			Status:         "ok",
-			Role:           role,
-			QueenID:        state.GetQueenId(),
-			Workers:        len(workers),
-			GeneratedAt:    state.GetGeneratedAt(),
-			TimeoutSeconds: state.GetTimeoutSeconds(),
-			WorkerStatuses: workers,
+
+		encoder := encoderForRequest(r)
+		w.Header().Set("Content-Type", encoder.ContentType())
+		encoder.Encode(statusBodyWriter(w, r), resp)
+	}
+}
+
+// statusETag content-addresses resp by hashing GeneratedAt and each
+// worker's ID/LastSeen (sorted by ID so worker order never changes the
+// result) -- enough to detect the changes a polling dashboard actually
+// cares about without re-encoding JSON just to compare bytes.
+func statusETag(resp StatusResponse) string {
+	ids := make([]string, len(resp.WorkerStatuses))
+	lastSeen := make(map[string]uint64, len(resp.WorkerStatuses))
+	for i, w := range resp.WorkerStatuses {
+		ids[i] = w.WorkerID
+		lastSeen[w.WorkerID] = w.LastSeen
+	}
+	sort.Strings(ids)
+
+	h := fnv.New64a()
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], resp.GeneratedAt)
+	h.Write(buf[:])
+	for _, id := range ids {
+		h.Write([]byte(id))
+		binary.BigEndian.PutUint64(buf[:], lastSeen[id])
+		h.Write(buf[:])
+	}
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// gzipWriterPool reuses gzip.Writers across requests; Status is on the hot
+// path for polling dashboards, so allocating a fresh compressor per request
+// would otherwise pointlessly churn the GC.
+var gzipWriterPool = sync.Pool{
+	New: func() any { return gzip.NewWriter(nil) },
+}
+
+// statusBodyWriter returns w unchanged unless the request's Accept-Encoding
+// advertises gzip, in which case it sets Content-Encoding and returns a
+// pooled gzip.Writer that is closed (flushing it) and returned to the pool
+// on the first Write the handler's Encode call makes; see
+// flushingGzipWriter.
+func statusBodyWriter(w http.ResponseWriter, r *http.Request) io.Writer {
+	if !acceptsGzip(r) {
+		return w
+	}
+	w.Header().Set("Content-Encoding", "gzip")
+	gz := gzipWriterPool.Get().(*gzip.Writer)
+	gz.Reset(w)
+	return &flushingGzipWriter{gz: gz}
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, enc := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		if strings.TrimSpace(enc) == "gzip" {
+			return true
 		}
+	}
+	return false
+}
+
+// flushingGzipWriter closes (flushing any buffered bytes) and returns its
+// gzip.Writer to gzipWriterPool on the first Write it sees -- json.Encode
+// makes exactly one Write call per response, so this is sufficient without
+// Status having to defer a Close itself.
+type flushingGzipWriter struct {
+	gz *gzip.Writer
+}
+
+func (f *flushingGzipWriter) Write(p []byte) (int, error) {
+	n, err := f.gz.Write(p)
+	if cerr := f.gz.Close(); cerr != nil && err == nil {
+		err = cerr
+	}
+	gzipWriterPool.Put(f.gz)
+	return n, err
+}
 
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(resp)
+// BuildStatusResponse converts a raw ClusterStateResponse into the same
+// JSON-friendly shape Status serves, minus Uptime (which only makes sense
+// relative to one server's start time). It is also used by the
+// /api/cluster/stream WebSocket bridge, which has no single http.Request
+// to hang an Uptime off of but otherwise wants GUI clients to see
+// identical cluster state whether they polled or are streaming it.
+func BuildStatusResponse(state *rpc.ClusterStateResponse) StatusResponse {
+	var workers []WorkerSummary
+	for _, wkr := range state.GetWorkers() {
+		workers = append(workers, WorkerSummary{
+			WorkerID:     wkr.GetWorkerId(),
+			Role:         wkr.GetRole(),
+			Status:       wkr.GetStatus(),
+			IP:           wkr.GetIp(),
+			Trust:        wkr.GetTrust(),
+			BootTS:       wkr.GetBootTs(),
+			LastSeen:     wkr.GetLastSeen(),
+			Capabilities: append([]string(nil), wkr.GetCapabilities()...),
+			GPU:          convertGPU(wkr.GetGpu()),
+		})
+	}
+
+	role := os.Getenv("COHESIX_ROLE")
+	if role == "" {
+		role = "Queen"
+	}
+	return StatusResponse{
+		Status:         "ok",
+		Role:           role,
+		QueenID:        state.GetQueenId(),
+		Workers:        len(workers),
+		GeneratedAt:    state.GetGeneratedAt(),
+		TimeoutSeconds: state.GetTimeoutSeconds(),
+		WorkerStatuses: workers,
 	}
 }
 
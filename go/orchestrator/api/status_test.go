@@ -0,0 +1,156 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: status_test.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package api
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cohesix/internal/orchestrator/rpc"
+)
+
+type timeoutClusterStateClient struct {
+	err *FetchTimeoutError
+}
+
+func (c *timeoutClusterStateClient) FetchClusterState(ctx context.Context) (*rpc.ClusterStateResponse, error) {
+	return nil, c.err
+}
+
+func TestStatusRendersFetchTimeoutErrorAs504(t *testing.T) {
+	client := &timeoutClusterStateClient{err: &FetchTimeoutError{Reason: "deadline elapsed"}}
+	req := httptest.NewRequest("GET", "/api/status", nil)
+	rec := httptest.NewRecorder()
+
+	Status(time.Now(), client)(rec, req)
+
+	if rec.Code != 504 {
+		t.Fatalf("expected 504, got %d", rec.Code)
+	}
+	var body fetchTimeoutResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Error != "fetch_timeout" {
+		t.Fatalf("unexpected error field: %q", body.Error)
+	}
+}
+
+type plainErrorClusterStateClient struct{}
+
+func (plainErrorClusterStateClient) FetchClusterState(ctx context.Context) (*rpc.ClusterStateResponse, error) {
+	return nil, errors.New("boom")
+}
+
+func TestStatusRendersOtherErrorsAs502(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/status", nil)
+	rec := httptest.NewRecorder()
+
+	Status(time.Now(), plainErrorClusterStateClient{})(rec, req)
+
+	if rec.Code != 502 {
+		t.Fatalf("expected 502, got %d", rec.Code)
+	}
+}
+
+func testClusterState() *rpc.ClusterStateResponse {
+	return &rpc.ClusterStateResponse{
+		QueenId:        "queen-a",
+		GeneratedAt:    100,
+		TimeoutSeconds: 10,
+		Workers: []*rpc.WorkerState{
+			{WorkerId: "worker-a", LastSeen: 1},
+		},
+	}
+}
+
+func TestStatusSetsETagAndCacheControl(t *testing.T) {
+	client := &sequencedClusterStateClient{states: []*rpc.ClusterStateResponse{testClusterState()}}
+	req := httptest.NewRequest("GET", "/api/status", nil)
+	rec := httptest.NewRecorder()
+
+	Status(time.Now(), client)(rec, req)
+
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected an ETag header")
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "max-age=5" {
+		t.Fatalf("unexpected Cache-Control: %q", got)
+	}
+}
+
+func TestStatusReturns304OnMatchingIfNoneMatch(t *testing.T) {
+	client := &sequencedClusterStateClient{states: []*rpc.ClusterStateResponse{testClusterState()}}
+	req := httptest.NewRequest("GET", "/api/status", nil)
+	rec := httptest.NewRecorder()
+	Status(time.Now(), client)(rec, req)
+	etag := rec.Header().Get("ETag")
+
+	req2 := httptest.NewRequest("GET", "/api/status", nil)
+	req2.Header.Set("If-None-Match", etag)
+	rec2 := httptest.NewRecorder()
+	Status(time.Now(), client)(rec2, req2)
+
+	if rec2.Code != 304 {
+		t.Fatalf("expected 304, got %d", rec2.Code)
+	}
+	if rec2.Body.Len() != 0 {
+		t.Fatalf("expected empty body on 304, got %q", rec2.Body.String())
+	}
+}
+
+func TestStatusChangesETagWhenWorkerLastSeenChanges(t *testing.T) {
+	first := testClusterState()
+	second := testClusterState()
+	second.Workers[0].LastSeen = 2
+	client := &sequencedClusterStateClient{states: []*rpc.ClusterStateResponse{first, second}}
+
+	rec1 := httptest.NewRecorder()
+	Status(time.Now(), client)(rec1, httptest.NewRequest("GET", "/api/status", nil))
+	rec2 := httptest.NewRecorder()
+	Status(time.Now(), client)(rec2, httptest.NewRequest("GET", "/api/status", nil))
+
+	if rec1.Header().Get("ETag") == rec2.Header().Get("ETag") {
+		t.Fatal("expected ETag to change when a worker's LastSeen changes")
+	}
+}
+
+func TestStatusGzipsResponseWhenAccepted(t *testing.T) {
+	client := &sequencedClusterStateClient{states: []*rpc.ClusterStateResponse{testClusterState()}}
+	req := httptest.NewRequest("GET", "/api/status", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+
+	Status(time.Now(), client)(rec, req)
+
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected Content-Encoding: gzip, got %q", rec.Header().Get("Content-Encoding"))
+	}
+	gz, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("new gzip reader: %v", err)
+	}
+	defer gz.Close()
+	raw, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("read gzip body: %v", err)
+	}
+	var resp StatusResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		t.Fatalf("decode gzip body: %v", err)
+	}
+	if resp.QueenID != "queen-a" {
+		t.Fatalf("unexpected queen_id: %q", resp.QueenID)
+	}
+}
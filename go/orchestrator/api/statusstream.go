@@ -0,0 +1,258 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: statusstream.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package api
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// EventType identifies the kind of delta a StatusBroadcaster emits.
+type EventType string
+
+const (
+	EventWorkerAdded     EventType = "worker.added"
+	EventWorkerRemoved   EventType = "worker.removed"
+	EventWorkerGPUUpdate EventType = "worker.gpu_update"
+	EventQueenChanged    EventType = "queen.changed"
+)
+
+// StatusEvent is one delta a StatusBroadcaster emits, in the shape both the
+// SSE and WebSocket status-stream handlers serialize to clients. ID is
+// monotonically increasing and is what a client echoes back as
+// Last-Event-ID to resume a dropped connection.
+type StatusEvent struct {
+	ID   uint64    `json:"id"`
+	Type EventType `json:"type"`
+	Data any       `json:"data"`
+}
+
+// WorkerAddedData is the Data payload of an EventWorkerAdded event.
+type WorkerAddedData struct {
+	Worker WorkerSummary `json:"worker"`
+}
+
+// WorkerRemovedData is the Data payload of an EventWorkerRemoved event.
+type WorkerRemovedData struct {
+	WorkerID string `json:"worker_id"`
+}
+
+// WorkerGPUUpdateData is the Data payload of an EventWorkerGPUUpdate event.
+type WorkerGPUUpdateData struct {
+	WorkerID string      `json:"worker_id"`
+	GPU      *GPUSummary `json:"gpu"`
+}
+
+// QueenChangedData is the Data payload of an EventQueenChanged event.
+type QueenChangedData struct {
+	QueenID string `json:"queen_id"`
+}
+
+const (
+	// DefaultStatusTickInterval is how often a StatusBroadcaster polls
+	// FetchClusterState when NewStatusBroadcaster is given a non-positive
+	// interval. Coalescing on a tick, rather than publishing every poll
+	// result unconditionally, means a worker flapping between two states
+	// faster than this still only costs subscribers one diff per tick.
+	DefaultStatusTickInterval = 2 * time.Second
+
+	// statusHistorySize bounds how many past events a StatusBroadcaster
+	// keeps for Last-Event-ID resume; older events are dropped, matching
+	// the drop-oldest backpressure orchestrator/http's WebSocket bridge
+	// already applies to slow consumers (see pumpClusterState).
+	statusHistorySize = 256
+
+	// statusSubscriberQueueSize bounds how many undelivered events are
+	// buffered per subscriber before the oldest is dropped.
+	statusSubscriberQueueSize = 32
+)
+
+// StatusSubscriber receives the StatusEvents a StatusBroadcaster publishes,
+// starting from just after whatever Last-Event-ID it resumed from.
+type StatusSubscriber struct {
+	events chan StatusEvent
+}
+
+// Events returns the channel StatusEvents arrive on. It is closed when the
+// subscriber is unsubscribed.
+func (s *StatusSubscriber) Events() <-chan StatusEvent { return s.events }
+
+// StatusBroadcaster polls a ClusterStateClient at TickInterval, diffs
+// successive snapshots into StatusEvents, and fans them out to many
+// subscribers over one shared FetchClusterState call per tick -- so N
+// dashboard/CLI subscribers don't multiply the gRPC fan-in the way N
+// independent pollers of /api/status would.
+type StatusBroadcaster struct {
+	client       ClusterStateClient
+	tickInterval time.Duration
+
+	mu          sync.Mutex
+	nextID      uint64
+	history     []StatusEvent
+	subscribers map[*StatusSubscriber]struct{}
+	last        StatusResponse
+	haveLast    bool
+}
+
+// NewStatusBroadcaster returns a broadcaster polling client every
+// tickInterval; a non-positive tickInterval uses DefaultStatusTickInterval.
+// Run must be called to start polling.
+func NewStatusBroadcaster(client ClusterStateClient, tickInterval time.Duration) *StatusBroadcaster {
+	if tickInterval <= 0 {
+		tickInterval = DefaultStatusTickInterval
+	}
+	return &StatusBroadcaster{
+		client:       client,
+		tickInterval: tickInterval,
+		subscribers:  make(map[*StatusSubscriber]struct{}),
+	}
+}
+
+// Run polls and publishes deltas until ctx is done. It establishes its
+// baseline snapshot with an immediate poll before waiting out the first
+// tick, rather than treating whenever the first tick happens to land as
+// "free" baseline setup: a caller that mutates the backing state shortly
+// after starting Run (as a subscriber connecting right away would) could
+// otherwise have that very change folded into the baseline and never
+// published as an event.
+func (b *StatusBroadcaster) Run(ctx context.Context) {
+	b.poll(ctx)
+	ticker := time.NewTicker(b.tickInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.poll(ctx)
+		}
+	}
+}
+
+func (b *StatusBroadcaster) poll(ctx context.Context) {
+	state, err := b.client.FetchClusterState(ctx)
+	if err != nil {
+		return
+	}
+	resp := BuildStatusResponse(state)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.haveLast {
+		b.last, b.haveLast = resp, true
+		return
+	}
+	events := diffStatus(b.last, resp)
+	b.last = resp
+	for _, event := range events {
+		b.publishLocked(event)
+	}
+}
+
+// diffStatus compares two successive StatusResponse snapshots into the
+// StatusEvents a subscriber needs to reconstruct the new state from the
+// old one, rather than resending the whole snapshot every tick.
+func diffStatus(old, next StatusResponse) []StatusEvent {
+	var events []StatusEvent
+
+	oldWorkers := make(map[string]WorkerSummary, len(old.WorkerStatuses))
+	for _, w := range old.WorkerStatuses {
+		oldWorkers[w.WorkerID] = w
+	}
+	seen := make(map[string]struct{}, len(next.WorkerStatuses))
+	for _, w := range next.WorkerStatuses {
+		seen[w.WorkerID] = struct{}{}
+		prev, existed := oldWorkers[w.WorkerID]
+		if !existed {
+			events = append(events, StatusEvent{Type: EventWorkerAdded, Data: WorkerAddedData{Worker: w}})
+			continue
+		}
+		if !reflect.DeepEqual(prev.GPU, w.GPU) {
+			events = append(events, StatusEvent{Type: EventWorkerGPUUpdate, Data: WorkerGPUUpdateData{WorkerID: w.WorkerID, GPU: w.GPU}})
+		}
+	}
+	for id := range oldWorkers {
+		if _, ok := seen[id]; !ok {
+			events = append(events, StatusEvent{Type: EventWorkerRemoved, Data: WorkerRemovedData{WorkerID: id}})
+		}
+	}
+	if next.QueenID != old.QueenID {
+		events = append(events, StatusEvent{Type: EventQueenChanged, Data: QueenChangedData{QueenID: next.QueenID}})
+	}
+	return events
+}
+
+// publishLocked must be called with b.mu held. It stamps event with the
+// next ID, appends it to history (trimming the oldest once over
+// statusHistorySize), and fans it out to every subscriber.
+func (b *StatusBroadcaster) publishLocked(event StatusEvent) {
+	b.nextID++
+	event.ID = b.nextID
+	b.history = append(b.history, event)
+	if len(b.history) > statusHistorySize {
+		b.history = b.history[len(b.history)-statusHistorySize:]
+	}
+	for sub := range b.subscribers {
+		sendDropOldest(sub.events, event)
+	}
+}
+
+// sendDropOldest delivers event to ch, dropping the oldest buffered event
+// first if ch is full -- the same backpressure trade-off
+// orchestrator/http's WebSocket bridge applies to a slow consumer (see
+// pumpClusterState), so one stalled subscriber can't block the others or
+// the poll loop.
+func sendDropOldest(ch chan StatusEvent, event StatusEvent) {
+	select {
+	case ch <- event:
+		return
+	default:
+	}
+	select {
+	case <-ch:
+	default:
+	}
+	select {
+	case ch <- event:
+	default:
+	}
+}
+
+// Subscribe registers a new subscriber, replaying any history events after
+// lastEventID that are still retained; past statusSubscriberQueueSize of
+// them only the newest are kept. A lastEventID of 0, for a client with
+// none, starts the subscriber from the current tip. One older than the
+// retained history replays whatever is still retained -- the same
+// drop-oldest trade-off as a slow consumer: a gap is never an error, just
+// lost history.
+func (b *StatusBroadcaster) Subscribe(lastEventID uint64) *StatusSubscriber {
+	sub := &StatusSubscriber{events: make(chan StatusEvent, statusSubscriberQueueSize)}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if lastEventID > 0 {
+		for _, event := range b.history {
+			if event.ID > lastEventID {
+				sendDropOldest(sub.events, event)
+			}
+		}
+	}
+	b.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// Unsubscribe removes sub and closes its event channel.
+func (b *StatusBroadcaster) Unsubscribe(sub *StatusSubscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+	delete(b.subscribers, sub)
+	close(sub.events)
+}
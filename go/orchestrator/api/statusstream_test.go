@@ -0,0 +1,162 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: statusstream_test.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cohesix/internal/orchestrator/rpc"
+)
+
+func workerSummary(id string, gpu *GPUSummary) WorkerSummary {
+	return WorkerSummary{WorkerID: id, Role: "DroneWorker", Status: "ready", GPU: gpu}
+}
+
+func TestDiffStatusDetectsWorkerAdded(t *testing.T) {
+	old := StatusResponse{WorkerStatuses: []WorkerSummary{workerSummary("worker-a", nil)}}
+	next := StatusResponse{WorkerStatuses: []WorkerSummary{workerSummary("worker-a", nil), workerSummary("worker-b", nil)}}
+
+	events := diffStatus(old, next)
+	if len(events) != 1 || events[0].Type != EventWorkerAdded {
+		t.Fatalf("expected one worker.added event, got %+v", events)
+	}
+	data, ok := events[0].Data.(WorkerAddedData)
+	if !ok || data.Worker.WorkerID != "worker-b" {
+		t.Fatalf("unexpected event data: %+v", events[0].Data)
+	}
+}
+
+func TestDiffStatusDetectsWorkerRemoved(t *testing.T) {
+	old := StatusResponse{WorkerStatuses: []WorkerSummary{workerSummary("worker-a", nil), workerSummary("worker-b", nil)}}
+	next := StatusResponse{WorkerStatuses: []WorkerSummary{workerSummary("worker-a", nil)}}
+
+	events := diffStatus(old, next)
+	if len(events) != 1 || events[0].Type != EventWorkerRemoved {
+		t.Fatalf("expected one worker.removed event, got %+v", events)
+	}
+	data, ok := events[0].Data.(WorkerRemovedData)
+	if !ok || data.WorkerID != "worker-b" {
+		t.Fatalf("unexpected event data: %+v", events[0].Data)
+	}
+}
+
+func TestDiffStatusDetectsGPUUpdate(t *testing.T) {
+	old := StatusResponse{WorkerStatuses: []WorkerSummary{workerSummary("worker-a", &GPUSummary{CurrentLoad: 10})}}
+	next := StatusResponse{WorkerStatuses: []WorkerSummary{workerSummary("worker-a", &GPUSummary{CurrentLoad: 90})}}
+
+	events := diffStatus(old, next)
+	if len(events) != 1 || events[0].Type != EventWorkerGPUUpdate {
+		t.Fatalf("expected one worker.gpu_update event, got %+v", events)
+	}
+	data, ok := events[0].Data.(WorkerGPUUpdateData)
+	if !ok || data.GPU.CurrentLoad != 90 {
+		t.Fatalf("unexpected event data: %+v", events[0].Data)
+	}
+}
+
+func TestDiffStatusDetectsQueenChanged(t *testing.T) {
+	old := StatusResponse{QueenID: "queen-a"}
+	next := StatusResponse{QueenID: "queen-b"}
+
+	events := diffStatus(old, next)
+	if len(events) != 1 || events[0].Type != EventQueenChanged {
+		t.Fatalf("expected one queen.changed event, got %+v", events)
+	}
+	data, ok := events[0].Data.(QueenChangedData)
+	if !ok || data.QueenID != "queen-b" {
+		t.Fatalf("unexpected event data: %+v", events[0].Data)
+	}
+}
+
+func TestDiffStatusNoChangesProducesNoEvents(t *testing.T) {
+	resp := StatusResponse{QueenID: "queen-a", WorkerStatuses: []WorkerSummary{workerSummary("worker-a", nil)}}
+	if events := diffStatus(resp, resp); len(events) != 0 {
+		t.Fatalf("expected no events, got %+v", events)
+	}
+}
+
+// sequencedClusterStateClient returns each of states in turn from
+// FetchClusterState, repeating the last one once exhausted -- enough to
+// drive StatusBroadcaster.poll across a few ticks in a test.
+type sequencedClusterStateClient struct {
+	states []*rpc.ClusterStateResponse
+	idx    int
+}
+
+func (c *sequencedClusterStateClient) FetchClusterState(ctx context.Context) (*rpc.ClusterStateResponse, error) {
+	state := c.states[c.idx]
+	if c.idx < len(c.states)-1 {
+		c.idx++
+	}
+	return state, nil
+}
+
+func TestStatusBroadcasterPollPublishesDiffEvents(t *testing.T) {
+	client := &sequencedClusterStateClient{states: []*rpc.ClusterStateResponse{
+		{QueenId: "queen-a", Workers: []*rpc.WorkerState{{WorkerId: "worker-a"}}},
+		{QueenId: "queen-b", Workers: []*rpc.WorkerState{{WorkerId: "worker-a"}, {WorkerId: "worker-b"}}},
+	}}
+	broadcaster := NewStatusBroadcaster(client, time.Hour)
+	sub := broadcaster.Subscribe(0)
+	defer broadcaster.Unsubscribe(sub)
+
+	ctx := context.Background()
+	broadcaster.poll(ctx) // establishes the baseline snapshot, no events yet
+	broadcaster.poll(ctx) // diffs against the baseline
+
+	seen := map[EventType]bool{}
+	for i := 0; i < 2; i++ {
+		select {
+		case event := <-sub.Events():
+			seen[event.Type] = true
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for event %d", i)
+		}
+	}
+	if !seen[EventWorkerAdded] || !seen[EventQueenChanged] {
+		t.Fatalf("expected worker.added and queen.changed events, got %+v", seen)
+	}
+}
+
+func TestStatusBroadcasterSubscribeResumesFromLastEventID(t *testing.T) {
+	broadcaster := NewStatusBroadcaster(&sequencedClusterStateClient{states: []*rpc.ClusterStateResponse{{}}}, time.Hour)
+
+	broadcaster.mu.Lock()
+	broadcaster.publishLocked(StatusEvent{Type: EventWorkerAdded, Data: WorkerAddedData{Worker: workerSummary("worker-a", nil)}})
+	broadcaster.publishLocked(StatusEvent{Type: EventWorkerAdded, Data: WorkerAddedData{Worker: workerSummary("worker-b", nil)}})
+	broadcaster.mu.Unlock()
+
+	sub := broadcaster.Subscribe(1)
+	defer broadcaster.Unsubscribe(sub)
+
+	select {
+	case event := <-sub.Events():
+		if event.ID != 2 {
+			t.Fatalf("expected only the event after lastEventID 1, got %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for replayed event")
+	}
+	select {
+	case event, ok := <-sub.Events():
+		if ok {
+			t.Fatalf("expected no further buffered events, got %+v", event)
+		}
+	default:
+	}
+}
+
+func TestStatusBroadcasterUnsubscribeClosesChannel(t *testing.T) {
+	broadcaster := NewStatusBroadcaster(&sequencedClusterStateClient{states: []*rpc.ClusterStateResponse{{}}}, time.Hour)
+	sub := broadcaster.Subscribe(0)
+	broadcaster.Unsubscribe(sub)
+	if _, ok := <-sub.Events(); ok {
+		t.Fatal("expected events channel to be closed after unsubscribe")
+	}
+}
@@ -0,0 +1,186 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: accesslog.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+// Package accesslog provides the orchestrator HTTP server's structured
+// access log: one JSON (or text) object per request, written through a
+// size/age-rotating file, replacing the old accessLogger's unstructured
+// "remote method path" lines with a record that also carries status,
+// byte count, duration, the authenticated caller, and a request ID that
+// ties a line back to the rest of that request's log output.
+package accesslog
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RequestIDHeader is both read (to honor an ID a caller or upstream proxy
+// already assigned) and written (so the response carries the ID this
+// request was logged under) on every request Middleware handles.
+const RequestIDHeader = "X-Request-ID"
+
+type ctxKey int
+
+const (
+	userKey ctxKey = iota
+	roleKey
+)
+
+// WithUser attaches the authenticated basic-auth username to ctx, for
+// Middleware to pick up when it writes the access log entry.
+func WithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userKey, user)
+}
+
+// UserFrom returns the username WithUser attached to ctx, or "".
+func UserFrom(ctx context.Context) string {
+	user, _ := ctx.Value(userKey).(string)
+	return user
+}
+
+// WithRole attaches the caller's resolved Cohesix role to ctx, for
+// Middleware to pick up when it writes the access log entry.
+func WithRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, roleKey, role)
+}
+
+// RoleFrom returns the role WithRole attached to ctx, or "".
+func RoleFrom(ctx context.Context) string {
+	role, _ := ctx.Value(roleKey).(string)
+	return role
+}
+
+// Config controls how Middleware encodes and rotates the access log.
+type Config struct {
+	// Format is "json" (the default) or "text".
+	Format string
+	// MaxSizeMB rotates the log out to a timestamped backup once it
+	// exceeds this size. 0 disables size-based rotation.
+	MaxSizeMB int
+	// MaxBackups caps how many rotated backups are kept; the oldest are
+	// removed past this count. 0 keeps them all.
+	MaxBackups int
+	// MaxAgeDays removes rotated backups older than this many days. 0
+	// disables age-based cleanup.
+	MaxAgeDays int
+}
+
+// Entry is one structured access log line.
+type Entry struct {
+	TS        string `json:"ts"`
+	Remote    string `json:"remote"`
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	Status    int    `json:"status"`
+	Bytes     int    `json:"bytes"`
+	DurMS     int64  `json:"dur_ms"`
+	User      string `json:"user,omitempty"`
+	Role      string `json:"role,omitempty"`
+	ReqID     string `json:"req_id"`
+	TLSPeerCN string `json:"tls_peer_cn,omitempty"`
+}
+
+// responseWriter wraps http.ResponseWriter to capture the status code and
+// byte count a handler writes, defaulting status to 200 the way net/http
+// itself does on the first Write with no prior WriteHeader call.
+//
+// It forwards Flush and Hijack to the underlying ResponseWriter when that
+// writer supports them: Middleware is the outermost handler whenever
+// Config.LogFile is set, ahead of the SSE and WebSocket streaming routes,
+// which type-assert their http.ResponseWriter to http.Flusher/http.Hijacker
+// and fail outright if that assertion doesn't see through this wrapper.
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += n
+	return n, err
+}
+
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("accesslog: underlying ResponseWriter does not support Hijack")
+	}
+	return h.Hijack()
+}
+
+// Middleware logs one Entry per request to out, encoded per cfg.Format.
+//
+// It must be the outermost middleware in the chain -- registered before
+// recoverMiddleware, not after -- so that a panic recovered further in
+// unwinds back into this handler's call to next.ServeHTTP and returns
+// normally (with the 500 recoverMiddleware wrote already on the
+// response), instead of unwinding straight past this frame and skipping
+// the log write entirely.
+func Middleware(out io.Writer, cfg Config) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reqID := r.Header.Get(RequestIDHeader)
+			if reqID == "" {
+				reqID = NewRequestID()
+			}
+			w.Header().Set(RequestIDHeader, reqID)
+
+			rec := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+			start := time.Now()
+			next.ServeHTTP(rec, r)
+
+			entry := Entry{
+				TS:     start.UTC().Format(time.RFC3339Nano),
+				Remote: r.RemoteAddr,
+				Method: r.Method,
+				Path:   r.URL.Path,
+				Status: rec.status,
+				Bytes:  rec.bytes,
+				DurMS:  time.Since(start).Milliseconds(),
+				User:   UserFrom(r.Context()),
+				Role:   RoleFrom(r.Context()),
+				ReqID:  reqID,
+			}
+			if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+				entry.TLSPeerCN = r.TLS.PeerCertificates[0].Subject.CommonName
+			}
+			writeEntry(out, entry, cfg.Format)
+		})
+	}
+}
+
+func writeEntry(out io.Writer, entry Entry, format string) {
+	if strings.EqualFold(format, "text") {
+		fmt.Fprintf(out, "%s %s %s %s %d %dB %dms user=%q role=%q req_id=%s tls_peer_cn=%q\n",
+			entry.TS, entry.Remote, entry.Method, entry.Path, entry.Status, entry.Bytes, entry.DurMS,
+			entry.User, entry.Role, entry.ReqID, entry.TLSPeerCN)
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	out.Write(append(data, '\n'))
+}
@@ -0,0 +1,167 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: accesslog_test.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package accesslog
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMiddlewareWritesJSONEntry(t *testing.T) {
+	var buf bytes.Buffer
+	mw := Middleware(&buf, Config{})
+
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := WithUser(r.Context(), "queen")
+		ctx = WithRole(ctx, "QueenPrimary")
+		*r = *r.WithContext(ctx)
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte("short and stout"))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/status", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Header().Get(RequestIDHeader) == "" {
+		t.Fatal("expected a request ID header to be set")
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry); err != nil {
+		t.Fatalf("unmarshal entry: %v (line: %s)", err, buf.String())
+	}
+	if entry.Status != http.StatusTeapot {
+		t.Fatalf("status = %d, want %d", entry.Status, http.StatusTeapot)
+	}
+	if entry.Bytes != len("short and stout") {
+		t.Fatalf("bytes = %d, want %d", entry.Bytes, len("short and stout"))
+	}
+	if entry.Path != "/api/status" || entry.Method != http.MethodGet {
+		t.Fatalf("unexpected request fields: %+v", entry)
+	}
+	if entry.ReqID == "" {
+		t.Fatal("expected a non-empty req_id")
+	}
+}
+
+func TestMiddlewarePropagatesClientRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	mw := Middleware(&buf, Config{})
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "client-supplied-id")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get(RequestIDHeader); got != "client-supplied-id" {
+		t.Fatalf("expected client-supplied request ID to be echoed, got %q", got)
+	}
+	var entry Entry
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry); err != nil {
+		t.Fatalf("unmarshal entry: %v", err)
+	}
+	if entry.ReqID != "client-supplied-id" {
+		t.Fatalf("entry req_id = %q, want client-supplied-id", entry.ReqID)
+	}
+}
+
+func TestMiddlewareRecordsStatusAfterRecoveredPanic(t *testing.T) {
+	var buf bytes.Buffer
+	mw := Middleware(&buf, Config{})
+
+	recovered := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				if recover() != nil {
+					http.Error(w, "internal server error", http.StatusInternalServerError)
+				}
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	// Middleware must wrap recovered, not the other way around, so the
+	// entry written below reflects the 500 the recover handler wrote.
+	handler := mw(recovered(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	var entry Entry
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry); err != nil {
+		t.Fatalf("unmarshal entry: %v (line: %s)", err, buf.String())
+	}
+	if entry.Status != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", entry.Status, http.StatusInternalServerError)
+	}
+}
+
+func TestMiddlewareTextFormat(t *testing.T) {
+	var buf bytes.Buffer
+	mw := Middleware(&buf, Config{Format: "text"})
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if strings.HasPrefix(buf.String(), "{") {
+		t.Fatalf("expected text format, got JSON: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "GET /") {
+		t.Fatalf("unexpected text line: %s", buf.String())
+	}
+}
+
+func TestNewRequestIDIsUniqueAndSortable(t *testing.T) {
+	a := NewRequestID()
+	b := NewRequestID()
+	if len(a) != 26 || len(b) != 26 {
+		t.Fatalf("expected 26-character ULIDs, got %d and %d", len(a), len(b))
+	}
+	if a == b {
+		t.Fatal("expected distinct request IDs")
+	}
+}
+
+func TestRotatorRotatesOnSize(t *testing.T) {
+	dir := t.TempDir()
+	r, err := NewRotator(filepath.Join(dir, "small.log"), 1, 2, 0)
+	if err != nil {
+		t.Fatalf("new rotator: %v", err)
+	}
+	defer r.Close()
+	r.size = 2 * 1024 * 1024 // pretend the file is already past MaxSizeMB
+
+	if _, err := r.Write([]byte("x")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	var sawBackup bool
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), "small-") {
+			sawBackup = true
+		}
+	}
+	if !sawBackup {
+		t.Fatalf("expected a rotated backup file, got: %v", entries)
+	}
+}
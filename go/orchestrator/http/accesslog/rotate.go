@@ -0,0 +1,160 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: rotate.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package accesslog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rotator is a lumberjack-style rotating file writer: Write appends to
+// Filename, rotating it out to a timestamped backup once it would exceed
+// MaxSizeMB, then pruning backups past MaxBackups or older than
+// MaxAgeDays.
+type Rotator struct {
+	Filename   string
+	MaxSizeMB  int
+	MaxBackups int
+	MaxAgeDays int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewRotator opens (creating if needed) filename for append and returns a
+// ready-to-use Rotator. A zero maxSizeMB/maxBackups/maxAgeDays disables
+// that dimension of rotation/cleanup.
+func NewRotator(filename string, maxSizeMB, maxBackups, maxAgeDays int) (*Rotator, error) {
+	r := &Rotator{Filename: filename, MaxSizeMB: maxSizeMB, MaxBackups: maxBackups, MaxAgeDays: maxAgeDays}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Rotator) open() error {
+	f, err := os.OpenFile(r.Filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.file = f
+	r.size = info.Size()
+	return nil
+}
+
+// Write implements io.Writer, rotating first if p would push the file past
+// MaxSizeMB.
+func (r *Rotator) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.MaxSizeMB > 0 && r.size+int64(len(p)) > int64(r.MaxSizeMB)*1024*1024 {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *Rotator) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(r.Filename, backupName(r.Filename, time.Now())); err != nil {
+		return err
+	}
+	if err := r.open(); err != nil {
+		return err
+	}
+	r.prune()
+	return nil
+}
+
+func backupName(filename string, t time.Time) string {
+	ext := filepath.Ext(filename)
+	base := strings.TrimSuffix(filename, ext)
+	return fmt.Sprintf("%s-%s%s", base, t.UTC().Format("20060102T150405.000000000"), ext)
+}
+
+type backupFile struct {
+	path    string
+	modTime time.Time
+}
+
+// prune removes rotated backups past MaxBackups or older than MaxAgeDays.
+// Errors removing an individual backup are ignored: a stale file left
+// behind is far less harmful than the access log going dark because
+// cleanup couldn't proceed.
+func (r *Rotator) prune() {
+	if r.MaxBackups <= 0 && r.MaxAgeDays <= 0 {
+		return
+	}
+	backups, err := r.listBackups()
+	if err != nil {
+		return
+	}
+	if r.MaxAgeDays > 0 {
+		cutoff := time.Now().AddDate(0, 0, -r.MaxAgeDays)
+		kept := backups[:0]
+		for _, b := range backups {
+			if b.modTime.Before(cutoff) {
+				os.Remove(b.path)
+				continue
+			}
+			kept = append(kept, b)
+		}
+		backups = kept
+	}
+	if r.MaxBackups > 0 && len(backups) > r.MaxBackups {
+		for _, b := range backups[:len(backups)-r.MaxBackups] {
+			os.Remove(b.path)
+		}
+	}
+}
+
+func (r *Rotator) listBackups() ([]backupFile, error) {
+	ext := filepath.Ext(r.Filename)
+	base := strings.TrimSuffix(filepath.Base(r.Filename), ext)
+	dir := filepath.Dir(r.Filename)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var backups []backupFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, base+"-") || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backupFile{path: filepath.Join(dir, name), modTime: info.ModTime()})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].modTime.Before(backups[j].modTime) })
+	return backups, nil
+}
+
+// Close implements io.Closer.
+func (r *Rotator) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
@@ -0,0 +1,57 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: ulid.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package accesslog
+
+import (
+	"crypto/rand"
+	"time"
+)
+
+// crockford is the Crockford base32 alphabet ULIDs encode with: it drops
+// I/L/O/U to avoid transcription mistakes with 1/1/0/V.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewRequestID returns a new ULID: a 48-bit millisecond timestamp followed
+// by 80 bits of randomness, Crockford base32 encoded to 26 characters.
+// Unlike a random UUID, ULIDs sort lexicographically by creation time, so
+// grepping an access log for a request ID also orders matches by when
+// they happened.
+func NewRequestID() string {
+	var id [16]byte
+	ms := uint64(time.Now().UnixMilli())
+	id[0] = byte(ms >> 40)
+	id[1] = byte(ms >> 32)
+	id[2] = byte(ms >> 24)
+	id[3] = byte(ms >> 16)
+	id[4] = byte(ms >> 8)
+	id[5] = byte(ms)
+	// A crypto/rand failure is effectively unrecoverable; a request ID is
+	// diagnostic rather than load-bearing, so fall back to whatever
+	// partially-filled entropy rand.Read left rather than panicking.
+	rand.Read(id[6:])
+	return encodeCrockford(id)
+}
+
+func encodeCrockford(data [16]byte) string {
+	var out [26]byte
+	var acc uint32
+	var bits, pos int
+	for _, b := range data {
+		acc = acc<<8 | uint32(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			out[pos] = crockford[(acc>>uint(bits))&0x1F]
+			pos++
+		}
+	}
+	if bits > 0 {
+		out[pos] = crockford[(acc<<uint(5-bits))&0x1F]
+		pos++
+	}
+	return string(out[:pos])
+}
@@ -0,0 +1,277 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: authfilter.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"cohesix/internal/jwtauth"
+	"cohesix/internal/logging"
+	"cohesix/internal/orchestrator/api"
+	"cohesix/internal/orchestrator/http/accesslog"
+)
+
+// Decision is the verdict one AuthFilter reaches for a single request.
+type Decision int
+
+const (
+	// Deny rejects the request once authChainMiddleware reaches it,
+	// short-circuiting the rest of the chain.
+	Deny Decision = iota
+	// Allow admits the request under the returned Principal's roles.
+	Allow
+)
+
+// Principal identifies an authenticated caller and the roles it was
+// authenticated as holding. MachineID is set only by filters authenticating
+// a self-enrolled worker (see MachineCertAuthFilter); authChainMiddleware
+// attaches it to the request context for ValidatingController to gate on.
+type Principal struct {
+	Name      string
+	Roles     []string
+	MachineID string
+}
+
+// AuthFilter authenticates one request. A filter that finds none of the
+// credentials it checks for (no Authorization header, no client cert, ...)
+// abstains by returning ErrNoCredentials rather than Deny, so
+// authChainMiddleware moves on to the next filter instead of rejecting the
+// request outright. Built-in filters: NewBasicAuthFilter,
+// NewIdentityAuthFilter, NewJWTAuthFilter, NewStaticAllowListFilter.
+type AuthFilter interface {
+	Name() string
+	Run(ctx context.Context, r *http.Request) (Principal, Decision, error)
+}
+
+// ErrNoCredentials is returned by an AuthFilter that found none of the
+// credentials it checks for on the request, as opposed to credentials it
+// checked and rejected.
+var ErrNoCredentials = errors.New("no credentials presented")
+
+var authChainLog = logging.New("orchestrator.authchain")
+
+// authChainMiddleware runs filters in order against each request and
+// composes the roles of every filter that Allows it. A filter Deny (for any
+// reason other than ErrNoCredentials) rejects the request immediately
+// without consulting the remaining filters. If every filter abstains with
+// ErrNoCredentials, the request is rejected as unauthenticated. Otherwise,
+// the first role across every Allowing principal that appears in
+// allowedRoles is recorded against the request for access logging, the same
+// contract identityMiddleware and bearerAuthMiddleware used to provide
+// individually.
+func authChainMiddleware(filters []AuthFilter, allowedRoles map[string]struct{}) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			log := authChainLog.With(logging.FieldRemoteAddr, r.RemoteAddr)
+			var matched []Principal
+			for _, filter := range filters {
+				principal, decision, err := filter.Run(r.Context(), r)
+				if errors.Is(err, ErrNoCredentials) {
+					continue
+				}
+				if decision != Allow {
+					log.Warn("auth filter denied request", "filter", filter.Name(), "error", err, logging.FieldStatusCode, http.StatusForbidden)
+					http.Error(w, "forbidden", http.StatusForbidden)
+					return
+				}
+				matched = append(matched, principal)
+			}
+			if len(matched) == 0 {
+				w.Header().Set("WWW-Authenticate", `Basic realm="restricted"`)
+				log.Warn("no auth filter matched", logging.FieldStatusCode, http.StatusUnauthorized)
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			var role, name, machineID string
+			for _, principal := range matched {
+				for _, candidate := range principal.Roles {
+					if _, ok := allowedRoles[candidate]; ok {
+						role, name, machineID = candidate, principal.Name, principal.MachineID
+						break
+					}
+				}
+				if role != "" {
+					break
+				}
+			}
+			if role == "" {
+				log.Warn("no matched principal carries a permitted role", logging.FieldStatusCode, http.StatusForbidden)
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+			ctx := accesslog.WithRole(r.Context(), role)
+			if name != "" {
+				ctx = accesslog.WithUser(ctx, name)
+			}
+			if machineID != "" {
+				ctx = api.WithMachineID(ctx, machineID)
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// BasicAuthFilter authenticates with a fixed HTTP Basic auth user/pass
+// pair, granting roles to any caller who presents it. It has no notion of
+// per-user identity, so roles is usually the server's full AllowedRoles
+// set -- the per-command role check still happens downstream in
+// api.Control via api.ControlAuthorizer.
+type BasicAuthFilter struct {
+	user  string
+	pass  string
+	roles []string
+}
+
+// NewBasicAuthFilter returns a filter that grants roles to any caller
+// presenting user/pass over HTTP Basic auth.
+func NewBasicAuthFilter(user, pass string, roles []string) *BasicAuthFilter {
+	return &BasicAuthFilter{user: user, pass: pass, roles: roles}
+}
+
+func (f *BasicAuthFilter) Name() string { return "basic" }
+
+// Run implements AuthFilter.
+func (f *BasicAuthFilter) Run(ctx context.Context, r *http.Request) (Principal, Decision, error) {
+	u, p, ok := r.BasicAuth()
+	if !ok {
+		return Principal{}, Deny, ErrNoCredentials
+	}
+	if u != f.user || p != f.pass {
+		return Principal{}, Deny, fmt.Errorf("invalid basic auth credentials")
+	}
+	return Principal{Name: u, Roles: f.roles}, Allow, nil
+}
+
+// IdentityAuthFilter derives identity and role from a verified mTLS client
+// certificate via an api.IdentityResolver, the filter-chain equivalent of
+// the former identityMiddleware.
+type IdentityAuthFilter struct {
+	resolver *api.IdentityResolver
+}
+
+// NewIdentityAuthFilter returns a filter that authenticates the caller's
+// verified client certificate against resolver.
+func NewIdentityAuthFilter(resolver *api.IdentityResolver) *IdentityAuthFilter {
+	return &IdentityAuthFilter{resolver: resolver}
+}
+
+func (f *IdentityAuthFilter) Name() string { return "mtls" }
+
+// Run implements AuthFilter.
+func (f *IdentityAuthFilter) Run(ctx context.Context, r *http.Request) (Principal, Decision, error) {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return Principal{}, Deny, ErrNoCredentials
+	}
+	cert := r.TLS.PeerCertificates[0]
+	role, err := f.resolver.Resolve(cert)
+	if err != nil {
+		return Principal{}, Deny, err
+	}
+	return Principal{Name: cert.Subject.String(), Roles: []string{role}}, Allow, nil
+}
+
+// JWTAuthFilter verifies an "Authorization: Bearer <token>" header against
+// a jwtauth.JWTVerifier, the filter-chain equivalent of the former
+// bearerAuthMiddleware.
+type JWTAuthFilter struct {
+	verifier *jwtauth.JWTVerifier
+}
+
+// NewJWTAuthFilter returns a filter that authenticates bearer tokens
+// against verifier.
+func NewJWTAuthFilter(verifier *jwtauth.JWTVerifier) *JWTAuthFilter {
+	return &JWTAuthFilter{verifier: verifier}
+}
+
+func (f *JWTAuthFilter) Name() string { return "jwt" }
+
+// Run implements AuthFilter.
+func (f *JWTAuthFilter) Run(ctx context.Context, r *http.Request) (Principal, Decision, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return Principal{}, Deny, ErrNoCredentials
+	}
+	claims, err := f.verifier.VerifyToken(token)
+	if errors.Is(err, jwtauth.ErrMalformedToken) {
+		// The bearer token isn't a JWT at all -- abstain instead of
+		// denying, so a later filter (e.g. StaticAllowListFilter) still
+		// gets a chance to authenticate it.
+		return Principal{}, Deny, ErrNoCredentials
+	}
+	if err != nil {
+		return Principal{}, Deny, err
+	}
+	return Principal{Name: claims.Subject, Roles: claims.Roles}, Allow, nil
+}
+
+// StaticAllowListFilter grants a fixed Principal to any request bearing one
+// of a static set of bearer tokens, for simple deployments that want to
+// admit a caller (e.g. a monitoring sidecar) without standing up mTLS or
+// JWT issuance.
+type StaticAllowListFilter struct {
+	principals map[string]Principal
+}
+
+// NewStaticAllowListFilter returns a filter granting roles[token] to any
+// caller presenting token as a bearer token.
+func NewStaticAllowListFilter(tokenRoles map[string][]string) *StaticAllowListFilter {
+	principals := make(map[string]Principal, len(tokenRoles))
+	for token, roles := range tokenRoles {
+		principals[token] = Principal{Name: token, Roles: roles}
+	}
+	return &StaticAllowListFilter{principals: principals}
+}
+
+func (f *StaticAllowListFilter) Name() string { return "static-allow-list" }
+
+// Run implements AuthFilter.
+func (f *StaticAllowListFilter) Run(ctx context.Context, r *http.Request) (Principal, Decision, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return Principal{}, Deny, ErrNoCredentials
+	}
+	principal, ok := f.principals[token]
+	if !ok {
+		return Principal{}, Deny, ErrNoCredentials
+	}
+	return principal, Allow, nil
+}
+
+// MachineCertAuthFilter authenticates a worker's mTLS client certificate
+// against a MachineRegistry of /api/enroll self-enrollments, rather than
+// IdentityAuthFilter's pre-provisioned IdentityBindings. It attaches the
+// matched machine_id to the request context so ValidatingController can
+// gate Execute on that machine's Validated flag.
+type MachineCertAuthFilter struct {
+	registry *api.MachineRegistry
+}
+
+// NewMachineCertAuthFilter returns a filter that authenticates client
+// certificates against registry.
+func NewMachineCertAuthFilter(registry *api.MachineRegistry) *MachineCertAuthFilter {
+	return &MachineCertAuthFilter{registry: registry}
+}
+
+func (f *MachineCertAuthFilter) Name() string { return "machine-cert" }
+
+// Run implements AuthFilter.
+func (f *MachineCertAuthFilter) Run(ctx context.Context, r *http.Request) (Principal, Decision, error) {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return Principal{}, Deny, ErrNoCredentials
+	}
+	fingerprint := api.CertFingerprint(r.TLS.PeerCertificates[0])
+	for _, machine := range f.registry.List() {
+		if machine.CertFingerprint == fingerprint {
+			return Principal{Name: machine.MachineID, Roles: machine.Roles, MachineID: machine.MachineID}, Allow, nil
+		}
+	}
+	return Principal{}, Deny, ErrNoCredentials
+}
@@ -0,0 +1,174 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: authfilter_test.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package http_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	orch "cohesix/internal/orchestrator/http"
+)
+
+func TestControlAcceptsStaticAllowListFilter(t *testing.T) {
+	gateway := newTestGateway()
+	srv, err := orch.New(orch.Config{
+		StaticDir:    "../../../static",
+		AllowedRoles: []string{"QueenPrimary"},
+		AuthFilters: []orch.AuthFilter{
+			orch.NewStaticAllowListFilter(map[string][]string{
+				"sidecar-token": {"QueenPrimary"},
+			}),
+		},
+		Controller:    gateway,
+		ClusterClient: gateway,
+	})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	ts := httptest.NewServer(srv.Router())
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/api/control", bytes.NewBufferString(`{"command":"assign-role","worker_id":"worker-a","role":"QueenPrimary"}`))
+	req.Header.Set("Authorization", "Bearer sidecar-token")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("post control: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+}
+
+func TestControlMixesBasicAuthWithStaticAllowListFilter(t *testing.T) {
+	gateway := newTestGateway()
+	srv, err := orch.New(orch.Config{
+		StaticDir:    "../../../static",
+		AuthUser:     "queen",
+		AuthPass:     "secret",
+		AllowedRoles: []string{"QueenPrimary"},
+		AuthFilters: []orch.AuthFilter{
+			orch.NewStaticAllowListFilter(map[string][]string{
+				"sidecar-token": {"QueenPrimary"},
+			}),
+		},
+		Controller:    gateway,
+		ClusterClient: gateway,
+	})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	ts := httptest.NewServer(srv.Router())
+	defer ts.Close()
+
+	// The static allow-list filter is layered on after the Basic auth
+	// filter AuthUser/AuthPass configures, so either credential admits the
+	// request without the deployer replacing one auth method with another.
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/api/control", bytes.NewBufferString(`{"command":"assign-role","worker_id":"worker-a","role":"QueenPrimary"}`))
+	req.Header.Set("Authorization", "Bearer sidecar-token")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("post control: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	req2, _ := http.NewRequest(http.MethodPost, ts.URL+"/api/control", bytes.NewBufferString(`{"command":"assign-role","worker_id":"worker-a","role":"QueenPrimary"}`))
+	req2.SetBasicAuth("queen", "secret")
+	resp2, err := http.DefaultClient.Do(req2)
+	if err != nil {
+		t.Fatalf("post control: %v", err)
+	}
+	defer resp2.Body.Close()
+	if resp2.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp2.StatusCode)
+	}
+}
+
+func TestControlRejectsUnknownStaticAllowListToken(t *testing.T) {
+	gateway := newTestGateway()
+	srv, err := orch.New(orch.Config{
+		StaticDir:    "../../../static",
+		AllowedRoles: []string{"QueenPrimary"},
+		AuthFilters: []orch.AuthFilter{
+			orch.NewStaticAllowListFilter(map[string][]string{
+				"sidecar-token": {"QueenPrimary"},
+			}),
+		},
+		Controller:    gateway,
+		ClusterClient: gateway,
+	})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	ts := httptest.NewServer(srv.Router())
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/api/control", bytes.NewBufferString(`{"command":"assign-role","worker_id":"worker-a","role":"QueenPrimary"}`))
+	req.Header.Set("Authorization", "Bearer wrong-token")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("post control: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", resp.StatusCode)
+	}
+}
+
+func TestStatusAcceptsBasicAuthWhileControlRequiresJWT(t *testing.T) {
+	pubPEM, privPEM := generateJWTTestKeyPair(t)
+
+	gateway := newTestGateway()
+	srv, err := orch.New(orch.Config{
+		StaticDir:     "../../../static",
+		JWTPublicKey:  pubPEM,
+		JWTSecret:     privPEM,
+		AuthUser:      "queen",
+		AuthPass:      "secret",
+		AllowedRoles:  []string{"QueenPrimary"},
+		Controller:    gateway,
+		ClusterClient: gateway,
+	})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	ts := httptest.NewServer(srv.Router())
+	defer ts.Close()
+
+	// /api/control picked JWT as its built-in filter (JWTPublicKey takes
+	// precedence over AuthUser/AuthPass), so Basic auth alone must not
+	// admit it.
+	controlReq, _ := http.NewRequest(http.MethodPost, ts.URL+"/api/control", bytes.NewBufferString(`{"command":"assign-role","worker_id":"worker-a","role":"QueenPrimary"}`))
+	controlReq.SetBasicAuth("queen", "secret")
+	controlResp, err := http.DefaultClient.Do(controlReq)
+	if err != nil {
+		t.Fatalf("post control: %v", err)
+	}
+	defer controlResp.Body.Close()
+	if controlResp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected control to reject basic auth with 401, got %d", controlResp.StatusCode)
+	}
+
+	// /api/status runs its own chain, built from AuthUser/AuthPass
+	// regardless of control's choice, so the same Basic credentials admit
+	// it here.
+	statusReq, _ := http.NewRequest(http.MethodGet, ts.URL+"/api/status", nil)
+	statusReq.SetBasicAuth("queen", "secret")
+	statusResp, err := http.DefaultClient.Do(statusReq)
+	if err != nil {
+		t.Fatalf("get status: %v", err)
+	}
+	defer statusResp.Body.Close()
+	if statusResp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status to accept basic auth with 200, got %d", statusResp.StatusCode)
+	}
+}
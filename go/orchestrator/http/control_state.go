@@ -0,0 +1,128 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: control_state.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"cohesix/internal/logging"
+	"cohesix/internal/orchestrator/api"
+	"cohesix/internal/orchestrator/storage"
+)
+
+var controlStateLog = logging.New("orchestrator.control_state")
+
+// controlStateKey returns the storage key a control command's outcome is
+// recorded under, and whether the command is one Server.controller tracks
+// at all (an unrecognized command is left for api.Control to reject on its
+// own terms).
+func controlStateKey(req api.ControlRequest) (string, bool) {
+	switch req.Command {
+	case "assign-role":
+		if req.WorkerID == "" {
+			return "", false
+		}
+		return "role/" + req.WorkerID, true
+	case "update-trust":
+		if req.WorkerID == "" {
+			return "", false
+		}
+		return "trust/" + req.WorkerID, true
+	case "schedule":
+		if req.AgentID == "" {
+			return "", false
+		}
+		return "schedule/" + req.AgentID, true
+	default:
+		return "", false
+	}
+}
+
+// recordControlState wraps api.Control so every successful command is also
+// written through s.storage: role assignments, trust updates, and schedule
+// decisions become versioned keys instead of living only in whatever
+// in-memory state api.Controller happens to keep. A request that names an
+// If-Match revision on an assign-role command is treated as a compare-and
+// swap against that recorded state, so two callers racing to reassign the
+// same worker can't silently clobber each other even though the actual
+// command still executes against the remote controller.
+func (s *Server) recordControlState(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.storage == nil || r.Body == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		data, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(data))
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		var req api.ControlRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		key, tracked := controlStateKey(req)
+		if !tracked {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if req.Command == "assign-role" {
+			if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+				rev, err := strconv.ParseInt(ifMatch, 10, 64)
+				if err != nil {
+					http.Error(w, "invalid If-Match revision", http.StatusBadRequest)
+					return
+				}
+				if _, err := s.storage.PutIfUnmodified(r.Context(), key, data, rev); err != nil {
+					if errors.Is(err, storage.ErrConflict) {
+						http.Error(w, "role assignment has been modified since If-Match", http.StatusConflict)
+						return
+					}
+					controlStateLog.Warn("record control state failed", "error", err, "key", key)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		if rec.status == http.StatusOK {
+			if _, err := s.storage.Put(r.Context(), key, data); err != nil {
+				controlStateLog.Warn("record control state failed", "error", err, "key", key)
+			}
+		}
+	})
+}
+
+// controlStateHandler serves GET /api/control/state, listing every control
+// decision Server.controller has recorded so far (optionally narrowed by
+// the "prefix" query parameter, e.g. "role/" or "trust/").
+func (s *Server) controlStateHandler(w http.ResponseWriter, r *http.Request) {
+	if s.storage == nil {
+		http.Error(w, "control state storage unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	items, err := s.storage.List(r.Context(), r.URL.Query().Get("prefix"))
+	if err != nil {
+		controlStateLog.Error("list control state failed", "error", err)
+		http.Error(w, "list failed", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(items)
+}
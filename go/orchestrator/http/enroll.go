@@ -0,0 +1,136 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: enroll.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"cohesix/internal/logging"
+	"cohesix/internal/orchestrator/api"
+)
+
+var enrollLog = logging.New("orchestrator.enroll")
+
+// enrollTokenRequest is the body of POST /api/enroll/token.
+type enrollTokenRequest struct {
+	Roles      []string `json:"roles"`
+	TrustLevel string   `json:"trust_level,omitempty"`
+	TTLSeconds int      `json:"ttl_seconds,omitempty"`
+}
+
+type enrollTokenResponse struct {
+	Token     string `json:"token"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// enrollTokenHandler mints a one-time enrollment token a worker later
+// redeems via POST /api/enroll. It sits behind the same auth chain as
+// /api/control (see initRoutes), so only a caller already holding one of
+// AllowedRoles can issue tokens -- and that caller may only grant roles it
+// could itself act as.
+func (s *Server) enrollTokenHandler(w http.ResponseWriter, r *http.Request) {
+	log := enrollLog.With(logging.FieldRemoteAddr, r.RemoteAddr)
+	var req enrollTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("invalid enroll token request body", "error", err, logging.FieldStatusCode, http.StatusBadRequest)
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	if len(req.Roles) == 0 {
+		http.Error(w, "roles required", http.StatusBadRequest)
+		return
+	}
+	for _, role := range req.Roles {
+		if _, ok := s.allowedRoles[role]; !ok {
+			log.Warn("enroll token requested unpermitted role", logging.FieldRole, role, logging.FieldStatusCode, http.StatusForbidden)
+			http.Error(w, "role not permitted", http.StatusForbidden)
+			return
+		}
+	}
+	ttl := time.Duration(req.TTLSeconds) * time.Second
+	if ttl <= 0 {
+		ttl = s.cfg.EnrollTokenTTL
+	}
+	if ttl <= 0 {
+		ttl = defaultEnrollTokenTTL
+	}
+	tok, err := s.enrollTokens.Issue(req.Roles, req.TrustLevel, ttl)
+	if err != nil {
+		log.Error("issue enroll token failed", "error", err, logging.FieldStatusCode, http.StatusInternalServerError)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	log.Info("enroll token issued", logging.FieldStatusCode, http.StatusOK)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(enrollTokenResponse{Token: tok.Token, ExpiresAt: tok.ExpiresAt.Unix()})
+}
+
+// enrollRequest is the body of POST /api/enroll.
+type enrollRequest struct {
+	Token               string `json:"token"`
+	CSR                 string `json:"csr"`
+	HardwareFingerprint string `json:"hardware_fingerprint"`
+}
+
+type enrollResponse struct {
+	Certificate string   `json:"certificate"`
+	Roles       []string `json:"roles"`
+	TrustLevel  string   `json:"trust_level,omitempty"`
+}
+
+// enrollHandler exchanges a valid enrollment token and CSR for a signed
+// client certificate, recording the new machine in the registry as
+// unvalidated. It has no auth gate of its own -- the redeemable,
+// single-use token is the credential -- so it must never be reachable
+// without EnrollEnabled's CA material configured; see New.
+func (s *Server) enrollHandler(w http.ResponseWriter, r *http.Request) {
+	log := enrollLog.With(logging.FieldRemoteAddr, r.RemoteAddr)
+	var req enrollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("invalid enroll request body", "error", err, logging.FieldStatusCode, http.StatusBadRequest)
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(req.HardwareFingerprint) == "" {
+		http.Error(w, "hardware_fingerprint required", http.StatusBadRequest)
+		return
+	}
+	tok, err := s.enrollTokens.Redeem(req.Token)
+	if err != nil {
+		log.Warn("enroll token redemption failed", "error", err, logging.FieldStatusCode, http.StatusUnauthorized)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	certTTL := s.cfg.EnrollCertTTL
+	if certTTL <= 0 {
+		certTTL = defaultEnrollCertTTL
+	}
+	certPEM, fingerprint, err := api.SignEnrollmentCSR([]byte(req.CSR), s.enrollCACert, s.enrollCAKey, certTTL)
+	if err != nil {
+		log.Warn("csr signing failed", "error", err, logging.FieldStatusCode, http.StatusBadRequest)
+		http.Error(w, "invalid csr", http.StatusBadRequest)
+		return
+	}
+	machine := api.MachineIdentity{
+		MachineID:       req.HardwareFingerprint,
+		Roles:           tok.Roles,
+		CertFingerprint: fingerprint,
+		EnrolledAt:      time.Now(),
+		Validated:       false,
+	}
+	if err := s.machines.Enroll(machine); err != nil {
+		log.Error("persist machine identity failed", "error", err, logging.FieldStatusCode, http.StatusInternalServerError)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	log.Info("machine enrolled, pending validation", "machine_id", machine.MachineID, logging.FieldStatusCode, http.StatusOK)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(enrollResponse{Certificate: string(certPEM), Roles: tok.Roles, TrustLevel: tok.TrustLevel})
+}
@@ -0,0 +1,242 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: enroll_test.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package http_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	orch "cohesix/internal/orchestrator/http"
+)
+
+// newTestEnrollCA mints a self-signed CA and writes its cert/key to PEM
+// files, the same material EnrollEnabled reuses as the signing CA for
+// worker CSRs rather than requiring a second keypair.
+func newTestEnrollCA(t *testing.T) (certPath, keyPath string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ca key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "cohesix-enroll-test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create ca cert: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal ca key: %v", err)
+	}
+	dir := t.TempDir()
+	certPath = filepath.Join(dir, "ca-cert.pem")
+	keyPath = filepath.Join(dir, "ca-key.pem")
+	writePEM(t, certPath, "CERTIFICATE", der)
+	writePEM(t, keyPath, "EC PRIVATE KEY", keyDER)
+	return certPath, keyPath
+}
+
+func newTestWorkerCSR(t *testing.T) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate worker key: %v", err)
+	}
+	tmpl := &x509.CertificateRequest{Subject: pkix.Name{CommonName: "worker-1"}}
+	der, err := x509.CreateCertificateRequest(rand.Reader, tmpl, key)
+	if err != nil {
+		t.Fatalf("create csr: %v", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := pem.Encode(buf, &pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}); err != nil {
+		t.Fatalf("encode csr: %v", err)
+	}
+	return buf.String()
+}
+
+func TestEnrollRedeemsTokenAndSignsWorkerCert(t *testing.T) {
+	gateway := newTestGateway()
+	caCert, caKey := newTestEnrollCA(t)
+	srv, err := orch.New(orch.Config{
+		StaticDir:     "../../../static",
+		AuthUser:      "queen",
+		AuthPass:      "secret",
+		AllowedRoles:  []string{"QueenPrimary"},
+		TLSCertFile:   caCert,
+		TLSKeyFile:    caKey,
+		EnrollEnabled: true,
+		MachinesFile:  filepath.Join(t.TempDir(), "machines.json"),
+		Controller:    gateway,
+		ClusterClient: gateway,
+	})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	ts := httptest.NewServer(srv.Router())
+	defer ts.Close()
+
+	tokenReq, _ := http.NewRequest(http.MethodPost, ts.URL+"/api/enroll/token", bytes.NewBufferString(`{"roles":["QueenPrimary"]}`))
+	tokenReq.SetBasicAuth("queen", "secret")
+	tokenResp, err := http.DefaultClient.Do(tokenReq)
+	if err != nil {
+		t.Fatalf("post enroll token: %v", err)
+	}
+	defer tokenResp.Body.Close()
+	if tokenResp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", tokenResp.StatusCode)
+	}
+	var tokenOut struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(tokenResp.Body).Decode(&tokenOut); err != nil {
+		t.Fatalf("decode token response: %v", err)
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"token":                tokenOut.Token,
+		"csr":                  newTestWorkerCSR(t),
+		"hardware_fingerprint": "worker-1",
+	})
+	if err != nil {
+		t.Fatalf("marshal enroll request: %v", err)
+	}
+	enrollResp, err := http.Post(ts.URL+"/api/enroll", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("post enroll: %v", err)
+	}
+	defer enrollResp.Body.Close()
+	if enrollResp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", enrollResp.StatusCode)
+	}
+	var enrollOut struct {
+		Certificate string   `json:"certificate"`
+		Roles       []string `json:"roles"`
+	}
+	if err := json.NewDecoder(enrollResp.Body).Decode(&enrollOut); err != nil {
+		t.Fatalf("decode enroll response: %v", err)
+	}
+	if enrollOut.Certificate == "" {
+		t.Fatal("expected a signed certificate")
+	}
+	if len(enrollOut.Roles) != 1 || enrollOut.Roles[0] != "QueenPrimary" {
+		t.Fatalf("unexpected roles: %v", enrollOut.Roles)
+	}
+}
+
+func TestEnrollRejectsReplayedToken(t *testing.T) {
+	gateway := newTestGateway()
+	caCert, caKey := newTestEnrollCA(t)
+	srv, err := orch.New(orch.Config{
+		StaticDir:     "../../../static",
+		AuthUser:      "queen",
+		AuthPass:      "secret",
+		AllowedRoles:  []string{"QueenPrimary"},
+		TLSCertFile:   caCert,
+		TLSKeyFile:    caKey,
+		EnrollEnabled: true,
+		MachinesFile:  filepath.Join(t.TempDir(), "machines.json"),
+		Controller:    gateway,
+		ClusterClient: gateway,
+	})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	ts := httptest.NewServer(srv.Router())
+	defer ts.Close()
+
+	tokenReq, _ := http.NewRequest(http.MethodPost, ts.URL+"/api/enroll/token", bytes.NewBufferString(`{"roles":["QueenPrimary"]}`))
+	tokenReq.SetBasicAuth("queen", "secret")
+	tokenResp, err := http.DefaultClient.Do(tokenReq)
+	if err != nil {
+		t.Fatalf("post enroll token: %v", err)
+	}
+	defer tokenResp.Body.Close()
+	var tokenOut struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(tokenResp.Body).Decode(&tokenOut); err != nil {
+		t.Fatalf("decode token response: %v", err)
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"token":                tokenOut.Token,
+		"csr":                  newTestWorkerCSR(t),
+		"hardware_fingerprint": "worker-1",
+	})
+	if err != nil {
+		t.Fatalf("marshal enroll request: %v", err)
+	}
+	first, err := http.Post(ts.URL+"/api/enroll", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("post enroll: %v", err)
+	}
+	first.Body.Close()
+	if first.StatusCode != http.StatusOK {
+		t.Fatalf("expected first enroll to succeed, got %d", first.StatusCode)
+	}
+
+	second, err := http.Post(ts.URL+"/api/enroll", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("post enroll: %v", err)
+	}
+	defer second.Body.Close()
+	if second.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected replayed token to be rejected with 401, got %d", second.StatusCode)
+	}
+}
+
+func TestEnrollTokenRejectsUnpermittedRole(t *testing.T) {
+	gateway := newTestGateway()
+	caCert, caKey := newTestEnrollCA(t)
+	srv, err := orch.New(orch.Config{
+		StaticDir:     "../../../static",
+		AuthUser:      "queen",
+		AuthPass:      "secret",
+		AllowedRoles:  []string{"QueenPrimary"},
+		TLSCertFile:   caCert,
+		TLSKeyFile:    caKey,
+		EnrollEnabled: true,
+		MachinesFile:  filepath.Join(t.TempDir(), "machines.json"),
+		Controller:    gateway,
+		ClusterClient: gateway,
+	})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	ts := httptest.NewServer(srv.Router())
+	defer ts.Close()
+
+	tokenReq, _ := http.NewRequest(http.MethodPost, ts.URL+"/api/enroll/token", bytes.NewBufferString(`{"roles":["HiveOverlord"]}`))
+	tokenReq.SetBasicAuth("queen", "secret")
+	tokenResp, err := http.DefaultClient.Do(tokenReq)
+	if err != nil {
+		t.Fatalf("post enroll token: %v", err)
+	}
+	defer tokenResp.Body.Close()
+	if tokenResp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", tokenResp.StatusCode)
+	}
+}
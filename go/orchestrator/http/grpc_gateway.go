@@ -0,0 +1,85 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: grpc_gateway.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-25
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"cohesix/internal/orchestrator/api"
+	"cohesix/internal/orchestrator/rpc"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+// mountGRPCGateway registers the grpc-gateway reverse proxy generated from
+// orchestrator.proto's google.api.http annotations under /v1, so REST
+// calls like POST /v1/workers/{worker_id}:assignRole reach the orchestrator
+// through the same rpc.OrchestratorServiceClient as /api/control, instead
+// of api.Control's command switch and GRPCGateway.Execute's RPC switch
+// having to be extended together by hand for every new command. The
+// legacy /api/control endpoint is unaffected and kept for back-compat.
+func (s *Server) mountGRPCGateway(client rpc.OrchestratorServiceClient) error {
+	mux := runtime.NewServeMux()
+	if err := rpc.RegisterOrchestratorServiceHandlerClient(context.Background(), mux, client); err != nil {
+		return fmt.Errorf("register grpc-gateway handlers: %w", err)
+	}
+	s.router.Mount("/v1", requireGatewayRole(s.roleAuthorizer, mux))
+	s.router.Get("/api/openapi.json", serveOpenAPI)
+	return nil
+}
+
+// requireGatewayRole applies the same RoleAuthorizer that gates
+// assign-role over /api/control to its grpc-gateway equivalent. There's no
+// pre-dispatch runtime.ServeMuxOption for rejecting a request outright, so
+// this runs as ordinary middleware wrapping the generated mux, peeking at
+// just enough of the assignRole request body to authorize it before
+// restoring the body for the handler to decode again.
+func requireGatewayRole(authorizer api.ControlAuthorizer, next http.Handler) http.Handler {
+	if authorizer == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, ":assignRole") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var payload struct {
+			Role string `json:"role"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			http.Error(w, "invalid json", http.StatusBadRequest)
+			return
+		}
+		if err := authorizer.Authorize(api.ControlRequest{Command: "assign-role", Role: payload.Role}); err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, api.ErrUnauthorizedRole) {
+				status = http.StatusForbidden
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func serveOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(rpc.SwaggerJSON)
+}
@@ -0,0 +1,273 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: identity_test.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-25
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package http_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cohesix/internal/orchestrator/api"
+	orch "cohesix/internal/orchestrator/http"
+)
+
+// testCA mints a self-signed CA plus a server cert and client certs signed
+// by it, writing everything to PEM files so it can be plugged into
+// orch.Config the same way an operator would.
+type testCA struct {
+	dir    string
+	caPEM  string
+	key    *ecdsa.PrivateKey
+	cert   *x509.Certificate
+	certDE []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ca key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "cohesix-test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create ca cert: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse ca cert: %v", err)
+	}
+	dir := t.TempDir()
+	caPath := filepath.Join(dir, "ca.pem")
+	writePEM(t, caPath, "CERTIFICATE", der)
+	return &testCA{dir: dir, caPEM: caPath, key: key, cert: cert, certDE: der}
+}
+
+// issue mints a leaf certificate signed by the CA with the given SPIFFE
+// URI SAN (empty for none), writing the cert and key to PEM files under
+// the CA's temp dir and returning their paths.
+func (ca *testCA) issue(t *testing.T, cn string, spiffeURI string) (certPath, keyPath string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate leaf key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"127.0.0.1", "localhost"},
+	}
+	if spiffeURI != "" {
+		u, err := url.Parse(spiffeURI)
+		if err != nil {
+			t.Fatalf("parse spiffe uri: %v", err)
+		}
+		tmpl.URIs = []*url.URL{u}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("create leaf cert: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal leaf key: %v", err)
+	}
+	certPath = filepath.Join(ca.dir, cn+"-cert.pem")
+	keyPath = filepath.Join(ca.dir, cn+"-key.pem")
+	writePEM(t, certPath, "CERTIFICATE", der)
+	writePEM(t, keyPath, "EC PRIVATE KEY", keyDER)
+	return certPath, keyPath
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	if err := pem.Encode(buf, &pem.Block{Type: blockType, Bytes: der}); err != nil {
+		t.Fatalf("encode pem: %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		t.Fatalf("write pem: %v", err)
+	}
+}
+
+func TestControlAcceptsBoundSPIFFEIdentity(t *testing.T) {
+	ca := newTestCA(t)
+	serverCert, serverKey := ca.issue(t, "orchestrator", "")
+	clientCert, clientKey := ca.issue(t, "queen-client", "spiffe://cohesix.internal/ns/prod/sa/queen")
+
+	gateway := newTestGateway()
+	cfg := orch.Config{
+		StaticDir:    "../../../static",
+		TLSCertFile:  serverCert,
+		TLSKeyFile:   serverKey,
+		TLSClientCA:  ca.caPEM,
+		AllowedRoles: []string{"QueenPrimary"},
+		IdentityBindings: []api.IdentityBinding{
+			{SPIFFEPath: "/ns/prod/sa/queen", Role: "QueenPrimary"},
+		},
+		TrustDomains:  []string{"cohesix.internal"},
+		Controller:    gateway,
+		ClusterClient: gateway,
+	}
+	srv, err := orch.New(cfg)
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+
+	ts := newMTLSServer(t, srv.Router(), ca)
+	defer ts.Close()
+
+	client := mtlsClient(t, clientCert, clientKey)
+	resp, err := client.Post(ts.URL+"/api/control", "application/json", bytes.NewBufferString(`{"command":"assign-role","worker_id":"worker-a","role":"QueenPrimary"}`))
+	if err != nil {
+		t.Fatalf("post control: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+}
+
+func TestControlRejectsUnboundIdentity(t *testing.T) {
+	ca := newTestCA(t)
+	serverCert, serverKey := ca.issue(t, "orchestrator", "")
+	clientCert, clientKey := ca.issue(t, "stranger-client", "spiffe://cohesix.internal/ns/prod/sa/stranger")
+
+	gateway := newTestGateway()
+	cfg := orch.Config{
+		StaticDir:    "../../../static",
+		TLSCertFile:  serverCert,
+		TLSKeyFile:   serverKey,
+		TLSClientCA:  ca.caPEM,
+		AllowedRoles: []string{"QueenPrimary"},
+		IdentityBindings: []api.IdentityBinding{
+			{SPIFFEPath: "/ns/prod/sa/queen", Role: "QueenPrimary"},
+		},
+		TrustDomains:  []string{"cohesix.internal"},
+		Controller:    gateway,
+		ClusterClient: gateway,
+	}
+	srv, err := orch.New(cfg)
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+
+	ts := newMTLSServer(t, srv.Router(), ca)
+	defer ts.Close()
+
+	client := mtlsClient(t, clientCert, clientKey)
+	resp, err := client.Post(ts.URL+"/api/control", "application/json", bytes.NewBufferString(`{"command":"assign-role","worker_id":"worker-a","role":"QueenPrimary"}`))
+	if err != nil {
+		t.Fatalf("post control: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", resp.StatusCode)
+	}
+}
+
+func TestControlRejectsUntrustedDomainIdentity(t *testing.T) {
+	ca := newTestCA(t)
+	otherCA := newTestCA(t)
+	serverCert, serverKey := ca.issue(t, "orchestrator", "")
+	// Signed by otherCA but still trusted by the server's client-CA pool
+	// (same root added to the test harness below) so the handshake
+	// succeeds and the rejection exercised is the trust-domain check, not
+	// TLS verification.
+	clientCert, clientKey := otherCA.issue(t, "evil-client", "spiffe://evil.example/ns/prod/sa/queen")
+
+	gateway := newTestGateway()
+	cfg := orch.Config{
+		StaticDir:    "../../../static",
+		TLSCertFile:  serverCert,
+		TLSKeyFile:   serverKey,
+		TLSClientCA:  ca.caPEM,
+		AllowedRoles: []string{"QueenPrimary"},
+		IdentityBindings: []api.IdentityBinding{
+			{SPIFFEPath: "/ns/prod/sa/queen", Role: "QueenPrimary"},
+		},
+		TrustDomains:  []string{"cohesix.internal"},
+		Controller:    gateway,
+		ClusterClient: gateway,
+	}
+	srv, err := orch.New(cfg)
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+
+	ts := newMTLSServerWithPool(t, srv.Router(), ca, otherCA)
+	defer ts.Close()
+
+	client := mtlsClient(t, clientCert, clientKey)
+	resp, err := client.Post(ts.URL+"/api/control", "application/json", bytes.NewBufferString(`{"command":"assign-role","worker_id":"worker-a","role":"QueenPrimary"}`))
+	if err != nil {
+		t.Fatalf("post control: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", resp.StatusCode)
+	}
+}
+
+func newMTLSServer(t *testing.T, handler http.Handler, ca *testCA) *httptest.Server {
+	return newMTLSServerWithPool(t, handler, ca)
+}
+
+// newMTLSServerWithPool starts a TLS test server whose listener requires
+// and verifies client certificates against acceptCAs.
+func newMTLSServerWithPool(t *testing.T, handler http.Handler, acceptCAs ...*testCA) *httptest.Server {
+	t.Helper()
+	pool := x509.NewCertPool()
+	for _, ca := range acceptCAs {
+		pool.AddCert(ca.cert)
+	}
+	ts := httptest.NewUnstartedServer(handler)
+	ts.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	ts.StartTLS()
+	return ts
+}
+
+func mtlsClient(t *testing.T, certPath, keyPath string) *http.Client {
+	t.Helper()
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		t.Fatalf("load client cert: %v", err)
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				Certificates:       []tls.Certificate{cert},
+				InsecureSkipVerify: true,
+			},
+		},
+	}
+}
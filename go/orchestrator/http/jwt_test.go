@@ -0,0 +1,184 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: jwt_test.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package http_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cohesix/internal/jwtauth"
+	orch "cohesix/internal/orchestrator/http"
+)
+
+func generateJWTTestKeyPair(t *testing.T) (pubPEM, privPEM string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	pubBytes := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+	privBytes := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return string(pubBytes), string(privBytes)
+}
+
+func TestControlAcceptsValidBearerToken(t *testing.T) {
+	pubPEM, privPEM := generateJWTTestKeyPair(t)
+	verifier, err := jwtauth.NewJWTVerifier([]byte(pubPEM), []byte(privPEM), "cohesix-queen", "cohesix-cluster")
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+	token, err := verifier.MintToken("worker-a", []string{"QueenPrimary"}, time.Minute)
+	if err != nil {
+		t.Fatalf("mint token: %v", err)
+	}
+
+	gateway := newTestGateway()
+	srv, err := orch.New(orch.Config{
+		StaticDir:     "../../../static",
+		JWTPublicKey:  pubPEM,
+		JWTIssuer:     "cohesix-queen",
+		JWTAudience:   "cohesix-cluster",
+		AllowedRoles:  []string{"QueenPrimary"},
+		Controller:    gateway,
+		ClusterClient: gateway,
+	})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+
+	ts := httptest.NewServer(srv.Router())
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/api/control", bytes.NewBufferString(`{"command":"assign-role","worker_id":"worker-a","role":"QueenPrimary"}`))
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("post control: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+}
+
+func TestControlRejectsMissingBearerToken(t *testing.T) {
+	pubPEM, privPEM := generateJWTTestKeyPair(t)
+
+	gateway := newTestGateway()
+	srv, err := orch.New(orch.Config{
+		StaticDir:     "../../../static",
+		JWTPublicKey:  pubPEM,
+		JWTSecret:     privPEM,
+		AllowedRoles:  []string{"QueenPrimary"},
+		Controller:    gateway,
+		ClusterClient: gateway,
+	})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+
+	ts := httptest.NewServer(srv.Router())
+	defer ts.Close()
+
+	resp, err := http.Post(ts.URL+"/api/control", "application/json", bytes.NewBufferString(`{"command":"assign-role","worker_id":"worker-a","role":"QueenPrimary"}`))
+	if err != nil {
+		t.Fatalf("post control: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", resp.StatusCode)
+	}
+}
+
+func TestControlMixesJWTAuthWithStaticAllowListFilter(t *testing.T) {
+	pubPEM, privPEM := generateJWTTestKeyPair(t)
+
+	gateway := newTestGateway()
+	srv, err := orch.New(orch.Config{
+		StaticDir:    "../../../static",
+		JWTPublicKey: pubPEM,
+		JWTSecret:    privPEM,
+		AllowedRoles: []string{"QueenPrimary"},
+		AuthFilters: []orch.AuthFilter{
+			orch.NewStaticAllowListFilter(map[string][]string{
+				"sidecar-token": {"QueenPrimary"},
+			}),
+		},
+		Controller:    gateway,
+		ClusterClient: gateway,
+	})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+
+	ts := httptest.NewServer(srv.Router())
+	defer ts.Close()
+
+	// sidecar-token isn't a JWT at all, so JWTAuthFilter (the built-in
+	// filter JWTPublicKey selects) must abstain rather than deny, letting
+	// the static allow-list filter behind it authenticate the request.
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/api/control", bytes.NewBufferString(`{"command":"assign-role","worker_id":"worker-a","role":"QueenPrimary"}`))
+	req.Header.Set("Authorization", "Bearer sidecar-token")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("post control: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+}
+
+func TestControlRejectsBearerTokenWithoutPermittedRole(t *testing.T) {
+	pubPEM, privPEM := generateJWTTestKeyPair(t)
+	verifier, err := jwtauth.NewJWTVerifier([]byte(pubPEM), []byte(privPEM), "", "")
+	if err != nil {
+		t.Fatalf("new verifier: %v", err)
+	}
+	token, err := verifier.MintToken("worker-a", []string{"DroneWorker"}, time.Minute)
+	if err != nil {
+		t.Fatalf("mint token: %v", err)
+	}
+
+	gateway := newTestGateway()
+	srv, err := orch.New(orch.Config{
+		StaticDir:     "../../../static",
+		JWTPublicKey:  pubPEM,
+		JWTSecret:     privPEM,
+		AllowedRoles:  []string{"QueenPrimary"},
+		Controller:    gateway,
+		ClusterClient: gateway,
+	})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+
+	ts := httptest.NewServer(srv.Router())
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/api/control", bytes.NewBufferString(`{"command":"assign-role","worker_id":"worker-a","role":"QueenPrimary"}`))
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("post control: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", resp.StatusCode)
+	}
+}
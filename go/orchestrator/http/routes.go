@@ -1,51 +1,102 @@
 // CLASSIFICATION: COMMUNITY
-// Filename: routes.go v0.1
+// Filename: routes.go v0.6
 // Author: Lukas Bower
-// Date Modified: 2025-07-21
+// Date Modified: 2026-07-26
 // License: SPDX-License-Identifier: MIT OR Apache-2.0
 
 package http
 
 import (
+	"encoding/json"
 	"net/http"
-	"time"
+	"path"
 
 	"cohesix/internal/orchestrator/api"
-	"cohesix/internal/orchestrator/static"
-	"github.com/go-chi/chi/v5"
-	"golang.org/x/time/rate"
 )
 
-// newRouter configures all routes and middleware.
-func newRouter(s *Server) *chi.Mux {
-	r := chi.NewRouter()
-	r.Use(recoverMiddleware())
-	if s.cfg.LogFile != "" {
-		r.Use(accessLogger(s.cfg.LogFile))
+// initRoutes wires every HTTP route onto s.router. It is called once from
+// New, after middleware-affecting config (auth, TLS, logging) has been
+// resolved.
+func (s *Server) initRoutes() {
+	r := s.router
+	// s.accessLog must be registered before recoverMiddleware: a panic
+	// recovered further in unwinds back into the access log handler's call
+	// to next.ServeHTTP and still gets logged with the 500 recoverMiddleware
+	// wrote, instead of unwinding past it and skipping the log write.
+	if s.accessLog != nil {
+		r.Use(s.accessLog)
 	}
+	r.Use(recoverMiddleware())
 	r.Use(s.requestCounter)
 
-	if !s.cfg.Dev && s.cfg.AuthUser != "" {
-		r.Use(basicAuthMiddleware(s.cfg.AuthUser, s.cfg.AuthPass))
+	metricsPath := s.cfg.MetricsPath
+	if metricsPath == "" {
+		metricsPath = "/metrics"
 	}
 
-	if !s.cfg.Dev {
-		s.controlLimiter = rate.NewLimiter(rate.Every(time.Minute/10), 10)
-	} else {
-		s.controlLimiter = rate.NewLimiter(rate.Inf, 0)
+	statusHandler := http.Handler(api.Status(s.start, s.statusClient))
+	if !s.cfg.Dev && len(s.statusAuthFilters) > 0 {
+		statusHandler = authChainMiddleware(s.statusAuthFilters, s.allowedRoles)(statusHandler)
 	}
-
-	r.Get("/api/status", api.Status)
+	r.Get("/api/status", statusHandler.ServeHTTP)
+	r.Get("/api/status/schema.json", api.SchemaHandler)
 	r.Get("/api/metrics", s.metricsHandler)
+	r.Get(metricsPath, s.metrics.Handler().ServeHTTP)
+	r.Get("/api/health", s.healthHandler)
+	r.Get("/api/stream", s.clusterStreamHandler)
+	// /api/cluster/stream is kept as an alias for existing clients.
+	r.Get("/api/cluster/stream", s.clusterStreamHandler)
+	r.Get("/api/control/state", s.controlStateHandler)
+	r.Get("/api/status/stream", s.statusStreamHandler)
+
+	var authMiddleware func(http.Handler) http.Handler
+	if !s.cfg.Dev && len(s.authFilters) > 0 {
+		authMiddleware = authChainMiddleware(s.authFilters, s.allowedRoles)
+	}
+
+	handler := s.recordControlState(api.Control(s.controller, s.roleAuthorizer))
+	if !s.cfg.Dev {
+		handler = s.rateLimitMiddleware(handler)
+		if authMiddleware != nil {
+			handler = authMiddleware(handler)
+		}
+	}
+	r.Post("/api/control", func(w http.ResponseWriter, r *http.Request) { handler.ServeHTTP(w, r) })
 
-	ctrl := rateLimitMiddleware(s.controlLimiter)(http.HandlerFunc(api.Control))
-	r.Post("/api/control", func(w http.ResponseWriter, r *http.Request) {
-		ctrl.ServeHTTP(w, r)
-	})
+	if s.cfg.EnrollEnabled {
+		// POST /api/enroll/token sits behind the same auth chain as
+		// /api/control -- only an already-trusted caller can mint
+		// enrollment tokens. POST /api/enroll has no gate of its own: the
+		// redeemable, single-use token it requires is the credential.
+		tokenHandler := http.Handler(http.HandlerFunc(s.enrollTokenHandler))
+		if authMiddleware != nil {
+			tokenHandler = authMiddleware(tokenHandler)
+		}
+		r.Post("/api/enroll/token", tokenHandler.ServeHTTP)
+		r.Post("/api/enroll", s.enrollHandler)
+	}
 
-	r.Handle("/static/*", static.FileHandler(s.cfg.StaticDir))
+	r.Handle("/static/*", http.StripPrefix("/static/", http.FileServer(http.Dir(s.cfg.StaticDir))))
 	r.NotFound(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		http.ServeFile(w, r, s.cfg.StaticDir+"/index.html")
+		http.ServeFile(w, r, path.Join(s.cfg.StaticDir, "index.html"))
 	}))
-	return r
+}
+
+type healthResponse struct {
+	Healthy bool `json:"healthy"`
+}
+
+// healthHandler lets the orchestrator UI distinguish "backend down" (503)
+// from "command rejected" (4xx from Control), which a bare HTTP status on
+// /api/control can't express on its own.
+func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
+	healthy := true
+	if checker, ok := s.controller.(interface{ Healthy() bool }); ok {
+		healthy = checker.Healthy()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if !healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(healthResponse{Healthy: healthy})
 }
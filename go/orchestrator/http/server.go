@@ -1,12 +1,14 @@
 // CLASSIFICATION: COMMUNITY
-// Filename: server.go v0.3
+// Filename: server.go v0.7
 // Author: Lukas Bower
-// Date Modified: 2029-02-21
+// Date Modified: 2026-07-26
 // License: SPDX-License-Identifier: MIT OR Apache-2.0
 
 package http
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
@@ -18,68 +20,202 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"golang.org/x/time/rate"
 
+	"cohesix/internal/jwtauth"
 	"cohesix/internal/orchestrator/api"
+	"cohesix/internal/orchestrator/http/accesslog"
+	"cohesix/internal/orchestrator/metrics"
+	"cohesix/internal/orchestrator/rpc"
+	"cohesix/internal/orchestrator/storage"
 	"github.com/go-chi/chi/v5"
 )
 
+// Logger abstracts logging for the orchestrator HTTP server; *log.Logger
+// satisfies it directly.
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
 // Config holds server configuration.
 type Config struct {
-	Bind          string
-	Port          int
-	StaticDir     string
-	AuthUser      string
-	AuthPass      string
-	LogFile       string
-	Dev           bool
-	GRPCEndpoint  string
-	RPCTimeout    time.Duration
-	Controller    api.Controller
-	ClusterClient api.ClusterStateClient
-	AllowedRoles  []string
-	TLSCertFile   string
-	TLSKeyFile    string
-	TLSClientCA   string
-	ControlRate   rate.Limit
-	ControlBurst  int
+	Bind             string
+	Port             int
+	StaticDir        string
+	AuthUser         string
+	AuthPass         string
+	LogFile          string
+	Dev              bool
+	GRPCEndpoint     string
+	RPCTimeout       time.Duration
+	Controller       api.Controller
+	ClusterClient    api.ClusterStateClient
+	AllowedRoles     []string
+	TLSCertFile      string
+	TLSKeyFile       string
+	TLSClientCA      string
+	ControlRate      rate.Limit
+	ControlBurst     int
+	IdentityBindings []api.IdentityBinding
+	TrustDomains     []string
+	// WSMaxMessageBytes is the deprecated name for StreamMaxMessageBytes,
+	// still honored when the latter is unset so existing configs keep
+	// working.
+	WSMaxMessageBytes int
+	// StreamMaxMessageBytes bounds the per-frame buffer /api/stream
+	// allows, defaulting to defaultWSMaxMessageBytes when both this and
+	// WSMaxMessageBytes are unset.
+	StreamMaxMessageBytes int
+	// JWTPublicKey is the PEM-encoded RSA public key bearer tokens are
+	// verified against. JWTSecret is the matching PEM-encoded private key;
+	// leave it empty to run verify-only, trusting tokens signed by a queen
+	// orchestrator holding the private key without being able to mint any
+	// itself. JWTIssuer/JWTAudience, when non-empty, are checked against
+	// each token's iss/aud claims.
+	JWTPublicKey string
+	JWTSecret    string
+	JWTIssuer    string
+	JWTAudience  string
+	// AuthFilters are appended after the AuthFilter built from
+	// AuthUser/AuthPass, IdentityBindings, or JWTPublicKey (whichever is
+	// configured), letting a deployer layer additional auth methods -- a
+	// static allow-list for a monitoring sidecar, say -- onto /api/control
+	// without replacing the built-in one. See authChainMiddleware.
+	AuthFilters []AuthFilter
+	// StatusAuthFilters gates GET /api/status the same way AuthFilters
+	// gates /api/control, but as an independent chain: a deployment can
+	// require mTLS/JWT for control actions while only asking read-only
+	// status for Basic auth (or nothing at all), instead of one global
+	// chain gating every route alike. AuthUser/AuthPass, when set, are
+	// built into this chain's Basic filter regardless of which built-in
+	// filter /api/control ends up using.
+	StatusAuthFilters []AuthFilter
+	// StorageDriver selects the Storage backend role assignments, trust
+	// updates, and schedule decisions are recorded through: "memory" (the
+	// default, and the only option Dev mode needs) or "etcd".
+	StorageDriver    string
+	StorageEndpoints []string
+	StorageTLS       *tls.Config
+	StoragePrefix    string
+	// LogFormat selects the access log encoding: "json" (the default) or
+	// "text". See accesslog.Config.
+	LogFormat string
+	// LogMaxSizeMB/LogMaxBackups/LogMaxAgeDays configure access log
+	// rotation; 0 disables that dimension. See accesslog.Rotator.
+	LogMaxSizeMB  int
+	LogMaxBackups int
+	LogMaxAgeDays int
+	// MetricsBuckets configures the http_request_duration_seconds
+	// histogram; empty uses metrics.DefaultBuckets.
+	MetricsBuckets []float64
+	// MetricsPath is where the standard Prometheus text-exposition format
+	// is always served, alongside /api/metrics (which serves JSON by
+	// default and Prometheus text only on request; see
+	// wantsPrometheusFormat). Defaults to "/metrics".
+	MetricsPath string
+	// EnrollEnabled turns on POST /api/enroll/token and POST /api/enroll
+	// for worker self-enrollment, signing CSRs with the TLSCertFile/
+	// TLSKeyFile pair as a CA. Both must be set when this is true.
+	EnrollEnabled bool
+	// MachinesFile persists enrolled machine identities; defaults to
+	// defaultMachinesFile ("/srv/orch_machines.json").
+	MachinesFile string
+	// EnrollTokenTTL bounds how long a POST /api/enroll/token token is
+	// valid for redemption via POST /api/enroll; defaults to
+	// defaultEnrollTokenTTL.
+	EnrollTokenTTL time.Duration
+	// EnrollCertTTL bounds the validity of client certificates
+	// SignEnrollmentCSR issues; defaults to defaultEnrollCertTTL.
+	EnrollCertTTL time.Duration
+	// StatusStreamTickInterval bounds how often /api/status/stream polls
+	// FetchClusterState to diff into worker/queen delta events; defaults to
+	// api.DefaultStatusTickInterval.
+	StatusStreamTickInterval time.Duration
+	// FetchTimeout bounds how long /api/status waits for FetchClusterState
+	// before giving up with a 504; defaults to api.DefaultFetchTimeout. See
+	// api.DeadlineClusterStateClient.
+	FetchTimeout time.Duration
 }
 
+// defaultMachinesFile/defaultEnrollTokenTTL/defaultEnrollCertTTL are the
+// Config.MachinesFile/EnrollTokenTTL/EnrollCertTTL fallbacks when those
+// fields are left unset.
+const (
+	defaultMachinesFile   = "/srv/orch_machines.json"
+	defaultEnrollTokenTTL = 10 * time.Minute
+	defaultEnrollCertTTL  = 365 * 24 * time.Hour
+)
+
 // Server wraps the HTTP server and router.
 type Server struct {
-	cfg            Config
-	router         *chi.Mux
-	start          time.Time
-	reqCnt         uint64
-	sessions       int64
-	controlLimiter *rate.Limiter
-	logger         Logger
-	controller     api.Controller
-	clusterClient  api.ClusterStateClient
-	closers        []io.Closer
-	roleAuthorizer api.ControlAuthorizer
-	controlAllowed uint64
-	controlDenied  uint64
-	tlsConfig      *tls.Config
+	cfg               Config
+	router            *chi.Mux
+	start             time.Time
+	reqCnt            uint64
+	sessions          int64
+	controlLimiter    *rate.Limiter
+	logger            Logger
+	controller        api.Controller
+	clusterClient     api.ClusterStateClient
+	closers           []io.Closer
+	roleAuthorizer    api.ControlAuthorizer
+	controlAllowed    uint64
+	controlDenied     uint64
+	tlsConfig         *tls.Config
+	rpcClient         rpc.OrchestratorServiceClient
+	metrics           *metrics.Registry
+	identityResolver  *api.IdentityResolver
+	jwtVerifier       *jwtauth.JWTVerifier
+	authFilters       []AuthFilter
+	statusAuthFilters []AuthFilter
+	allowedRoles      map[string]struct{}
+	storage           storage.Storage
+	accessLog         func(http.Handler) http.Handler
+	machines          *api.MachineRegistry
+	enrollTokens      *api.EnrollTokenIssuer
+	enrollCACert      []byte
+	enrollCAKey       []byte
+	statusBroadcaster *api.StatusBroadcaster
+	// rotatingClusterClient is the stable, swappable delegate Reload rotates
+	// onto a freshly redialed gateway; statusClient wraps it rather than
+	// clusterClient directly so a hot Reload is visible to /api/status
+	// without touching the route table. See api.RotatingClusterStateClient.
+	rotatingClusterClient *api.RotatingClusterStateClient
+	// statusClient is what /api/status actually reads from:
+	// rotatingClusterClient wrapped in a deadline so a slow gRPC peer can't
+	// starve HTTP workers. See api.DeadlineClusterStateClient.
+	statusClient *api.DeadlineClusterStateClient
+	// shutdownCh closes once Start's ctx is done, letting statusClient
+	// cancel any fetch still in flight at shutdown rather than leaving it
+	// to run past the point anything is still listening for the result.
+	shutdownCh chan struct{}
+
+	closersMu sync.Mutex
 }
 
 // New returns an initialized server.
 func New(cfg Config) (*Server, error) {
 	if !cfg.Dev {
-		if strings.TrimSpace(cfg.AuthUser) == "" || strings.TrimSpace(cfg.AuthPass) == "" {
-			return nil, fmt.Errorf("basic auth credentials are required outside dev mode")
+		hasBasicAuth := strings.TrimSpace(cfg.AuthUser) != "" && strings.TrimSpace(cfg.AuthPass) != ""
+		hasIdentityAuth := len(cfg.IdentityBindings) > 0
+		hasJWTAuth := strings.TrimSpace(cfg.JWTPublicKey) != ""
+		hasFilterAuth := len(cfg.AuthFilters) > 0
+		if !hasBasicAuth && !hasIdentityAuth && !hasJWTAuth && !hasFilterAuth {
+			return nil, fmt.Errorf("basic auth credentials, mTLS identity bindings, a jwt public key, or custom auth filters are required outside dev mode")
 		}
 	}
 
 	s := &Server{
-		cfg:    cfg,
-		router: chi.NewRouter(),
-		start:  time.Now(),
-		logger: log.Default(),
+		cfg:     cfg,
+		router:  chi.NewRouter(),
+		start:   time.Now(),
+		logger:  log.Default(),
+		metrics: metrics.New(cfg.MetricsBuckets),
 	}
 
 	limit := cfg.ControlRate
@@ -103,6 +239,55 @@ func New(cfg Config) (*Server, error) {
 		roles = []string{"QueenPrimary", "RegionalQueen", "BareMetalQueen"}
 	}
 	s.roleAuthorizer = api.NewRoleAuthorizer(roles)
+	s.allowedRoles = make(map[string]struct{}, len(roles))
+	for _, role := range roles {
+		s.allowedRoles[role] = struct{}{}
+	}
+
+	if len(cfg.IdentityBindings) > 0 {
+		s.identityResolver = api.NewIdentityResolver(cfg.IdentityBindings, cfg.TrustDomains)
+	}
+
+	if strings.TrimSpace(cfg.JWTPublicKey) != "" {
+		verifier, err := jwtauth.NewJWTVerifier([]byte(cfg.JWTPublicKey), []byte(cfg.JWTSecret), cfg.JWTIssuer, cfg.JWTAudience)
+		if err != nil {
+			return nil, fmt.Errorf("initialise jwt verifier: %w", err)
+		}
+		s.jwtVerifier = verifier
+	}
+
+	// The built-in filter is whichever of identity/JWT/basic auth is
+	// configured, in that preference order, matching the precedence the
+	// single-method middlewares used before AuthFilter existed. AuthFilters
+	// from Config are layered on after it, so a deployer can mix in a
+	// static allow-list or a second method without losing the built-in one.
+	switch {
+	case s.identityResolver != nil:
+		s.authFilters = append(s.authFilters, NewIdentityAuthFilter(s.identityResolver))
+	case s.jwtVerifier != nil:
+		s.authFilters = append(s.authFilters, NewJWTAuthFilter(s.jwtVerifier))
+	case cfg.AuthUser != "":
+		s.authFilters = append(s.authFilters, NewBasicAuthFilter(cfg.AuthUser, cfg.AuthPass, roles))
+	}
+	s.authFilters = append(s.authFilters, cfg.AuthFilters...)
+
+	// /api/status gets its own chain rather than reusing s.authFilters: a
+	// deployer who picked mTLS or JWT for /api/control still wants
+	// read-only status reachable with plain Basic auth, not a client
+	// certificate or bearer token. AuthUser/AuthPass feeds this chain's
+	// Basic filter independent of which built-in filter s.authFilters above
+	// chose.
+	if cfg.AuthUser != "" {
+		s.statusAuthFilters = append(s.statusAuthFilters, NewBasicAuthFilter(cfg.AuthUser, cfg.AuthPass, roles))
+	}
+	s.statusAuthFilters = append(s.statusAuthFilters, cfg.StatusAuthFilters...)
+
+	store, err := newStorage(cfg)
+	if err != nil {
+		return nil, err
+	}
+	s.storage = store
+	s.closers = append(s.closers, store)
 
 	if s.controller == nil || s.clusterClient == nil {
 		var (
@@ -121,10 +306,32 @@ func New(cfg Config) (*Server, error) {
 		}
 		s.controller = gateway
 		s.clusterClient = gateway
+		s.rpcClient = gateway.Client()
 		s.closers = append(s.closers, gateway)
 	}
+	s.statusBroadcaster = api.NewStatusBroadcaster(s.clusterClient, cfg.StatusStreamTickInterval)
+	s.metrics.Register(api.NewMetricsRegistry(s.clusterClient))
+	s.rotatingClusterClient = api.NewRotatingClusterStateClient(s.clusterClient)
+	s.shutdownCh = make(chan struct{})
+	s.statusClient = api.NewDeadlineClusterStateClient(s.rotatingClusterClient, cfg.FetchTimeout, s.shutdownCh)
+	if err := api.ValidateStatusSchemaSample(); err != nil {
+		return nil, fmt.Errorf("status_response no longer matches its embedded schema: %w", err)
+	}
 	if cfg.LogFile != "" {
-		s.router.Use(accessLogger(cfg.LogFile))
+		rotator, err := accesslog.NewRotator(cfg.LogFile, cfg.LogMaxSizeMB, cfg.LogMaxBackups, cfg.LogMaxAgeDays)
+		if err != nil {
+			return nil, fmt.Errorf("open access log: %w", err)
+		}
+		s.closers = append(s.closers, rotator)
+		s.accessLog = accesslog.Middleware(rotator, accesslog.Config{
+			Format:     cfg.LogFormat,
+			MaxSizeMB:  cfg.LogMaxSizeMB,
+			MaxBackups: cfg.LogMaxBackups,
+			MaxAgeDays: cfg.LogMaxAgeDays,
+		})
+	}
+	if s.identityResolver != nil && cfg.TLSClientCA == "" {
+		return nil, fmt.Errorf("tls_client_ca is required when identity_bindings are configured")
 	}
 	if cfg.TLSCertFile != "" || cfg.TLSKeyFile != "" || cfg.TLSClientCA != "" {
 		if cfg.TLSCertFile == "" || cfg.TLSKeyFile == "" {
@@ -136,30 +343,47 @@ func New(cfg Config) (*Server, error) {
 		}
 		s.tlsConfig = tlsCfg
 	}
+
+	if cfg.EnrollEnabled {
+		if cfg.TLSCertFile == "" || cfg.TLSKeyFile == "" {
+			return nil, fmt.Errorf("tls_cert_file and tls_key_file are required to sign enrollment CSRs")
+		}
+		caCertPEM, err := os.ReadFile(cfg.TLSCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("read enrollment ca certificate: %w", err)
+		}
+		caKeyPEM, err := os.ReadFile(cfg.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("read enrollment ca key: %w", err)
+		}
+		machinesFile := cfg.MachinesFile
+		if machinesFile == "" {
+			machinesFile = defaultMachinesFile
+		}
+		machines, err := api.NewMachineRegistry(machinesFile)
+		if err != nil {
+			return nil, fmt.Errorf("load machine registry: %w", err)
+		}
+		s.machines = machines
+		s.enrollCACert = caCertPEM
+		s.enrollCAKey = caKeyPEM
+		s.enrollTokens = api.NewEnrollTokenIssuer()
+		s.authFilters = append(s.authFilters, NewMachineCertAuthFilter(machines))
+		s.controller = &api.ValidatingController{Controller: s.controller, Registry: machines}
+	}
+
 	s.initRoutes()
+	if s.rpcClient != nil {
+		if err := s.mountGRPCGateway(s.rpcClient); err != nil {
+			return nil, err
+		}
+	}
 	return s, nil
 }
 
 // Router returns the underlying router, useful for tests.
 func (s *Server) Router() http.Handler { return s.router }
 
-func accessLogger(path string) func(http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
-		if err != nil {
-			log.Printf("open log: %v", err)
-			return next
-		}
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			next.ServeHTTP(w, r)
-			rec := fmt.Sprintf("%s %s %s\n", r.RemoteAddr, r.Method, r.URL.Path)
-			if _, err := f.Write([]byte(rec)); err != nil {
-				log.Printf("access log write: %v", err)
-			}
-		})
-	}
-}
-
 // Addr returns the listening address.
 func (s *Server) Addr() string { return net.JoinHostPort(s.cfg.Bind, fmt.Sprint(s.cfg.Port)) }
 
@@ -170,16 +394,27 @@ func (s *Server) Start(ctx context.Context) error {
 		switch st {
 		case http.StateNew:
 			atomic.AddInt64(&s.sessions, 1)
+			s.metrics.SessionOpened()
 		case http.StateClosed, http.StateHijacked:
 			atomic.AddInt64(&s.sessions, -1)
+			s.metrics.SessionClosed()
 		}
 	}
 	if s.cfg.Dev {
 		go watchStatic(s.cfg.StaticDir)
 	}
+	if s.statusBroadcaster != nil {
+		go s.statusBroadcaster.Run(ctx)
+	}
 	go func() {
 		<-ctx.Done()
-		for _, closer := range s.closers {
+		if s.shutdownCh != nil {
+			close(s.shutdownCh)
+		}
+		s.closersMu.Lock()
+		closers := s.closers
+		s.closersMu.Unlock()
+		for _, closer := range closers {
 			if err := closer.Close(); err != nil {
 				s.logger.Printf("close error: %v", err)
 			}
@@ -201,10 +436,112 @@ func (s *Server) Start(ctx context.Context) error {
 	return err
 }
 
+// Reload redials the orchestrator gRPC target -- re-resolving
+// Config.GRPCEndpoint or the COHESIX_ORCH_ADDR/COHESIX_ORCH_* environment
+// variables the same way New did -- and rotates s.rotatingClusterClient onto
+// the fresh gateway. It's meant to be driven by a SIGHUP (see
+// cmd/gui-orchestrator's newSignalContext reload channel), so an operator
+// can point the orchestrator at a new endpoint or rolled TLS material
+// without restarting the HTTP listener or dropping in-flight requests:
+// api.Status closed over s.statusClient, which itself wraps
+// s.rotatingClusterClient rather than any particular gateway, so existing
+// callers keep working against the old gateway until their request
+// completes while new ones pick up the swap immediately.
+//
+// Reload only rotates the gRPC-backed cluster state path. It is a no-op
+// when the server was built with a Config.ClusterClient override (as tests
+// do), since there is no gRPC target to redial.
+func (s *Server) Reload(ctx context.Context) error {
+	if s.cfg.ClusterClient != nil {
+		s.logger.Printf("reload: using an injected ClusterClient, nothing to redial")
+		return nil
+	}
+
+	timeout := s.cfg.RPCTimeout
+	var (
+		gateway *api.GRPCGateway
+		err     error
+	)
+	if s.cfg.GRPCEndpoint != "" {
+		gateway, err = api.NewGRPCGateway(ctx, s.cfg.GRPCEndpoint, timeout)
+	} else {
+		gateway, err = api.NewGRPCGatewayFromEnv(ctx, timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("reload: redial orchestrator gateway: %w", err)
+	}
+
+	prev := s.rotatingClusterClient.Swap(gateway)
+	s.closersMu.Lock()
+	s.closers = append(s.closers, gateway)
+	s.closersMu.Unlock()
+
+	// The gateway New dialed also backs s.controller, s.rpcClient, the
+	// status broadcaster and the metrics registry, none of which Reload
+	// rotates, so it stays open until shutdown.
+	if closer, ok := prev.(io.Closer); ok && closer != nil && prev != s.clusterClient {
+		// Give requests already in flight against the old gateway a chance
+		// to finish before its connection is torn down.
+		time.AfterFunc(timeout+time.Second, func() {
+			if err := closer.Close(); err != nil {
+				s.logger.Printf("reload: close previous gateway: %v", err)
+			}
+		})
+	}
+	s.logger.Printf("reload: rotated orchestrator gRPC gateway")
+	return nil
+}
+
+// statusRecorder wraps http.ResponseWriter to capture the status code a
+// handler writes, defaulting to 200 for handlers that never call
+// WriteHeader explicitly (as net/http itself does on the first Write).
+//
+// It forwards Flush and Hijack to the underlying ResponseWriter when that
+// writer supports them: requestCounter is installed ahead of every route,
+// including the SSE and WebSocket streaming handlers, which type-assert
+// their http.ResponseWriter to http.Flusher/http.Hijacker and fail outright
+// if that assertion doesn't see through this wrapper.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("statusRecorder: underlying ResponseWriter does not support Hijack")
+	}
+	return h.Hijack()
+}
+
 func (s *Server) requestCounter(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		atomic.AddUint64(&s.reqCnt, 1)
-		next.ServeHTTP(w, r)
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		// Label with the matched chi route pattern (e.g. "/static/*"), never
+		// the raw request path: an unmatched path falls through to
+		// r.NotFound with no pattern, and labeling those with the literal
+		// path would let scanners/bots blow up the metric's cardinality.
+		route := "unmatched"
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			if pattern := rctx.RoutePattern(); pattern != "" {
+				route = pattern
+			}
+		}
+		s.metrics.ObserveRequest(route, r.Method, rec.status, time.Since(start))
 	})
 }
 
@@ -219,7 +556,26 @@ type metricsResponse struct {
 	ControlDeniedTotal     uint64  `json:"control_denied_total"`
 }
 
+// wantsPrometheusFormat reports whether /api/metrics should render the
+// Prometheus text-exposition format (via ?format=prometheus, or an Accept
+// header naming text/plain without also naming application/json, as a
+// Prometheus server's scrape request does) instead of the default JSON
+// blob. The application/json exclusion keeps JSON clients that send a
+// permissive Accept list (e.g. "application/json, text/plain, */*") on
+// the response shape they actually expect.
+func wantsPrometheusFormat(r *http.Request) bool {
+	if strings.EqualFold(r.URL.Query().Get("format"), "prometheus") {
+		return true
+	}
+	accept := r.Header.Get("Accept")
+	return strings.Contains(accept, "text/plain") && !strings.Contains(accept, "application/json")
+}
+
 func (s *Server) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	if wantsPrometheusFormat(r) {
+		s.metrics.Handler().ServeHTTP(w, r)
+		return
+	}
 	limit := s.controlLimiter.Limit()
 	resp := metricsResponse{
 		RequestsTotal:          atomic.LoadUint64(&s.reqCnt),
@@ -277,32 +633,78 @@ func recoverMiddleware() func(http.Handler) http.Handler {
 	}
 }
 
-func basicAuthMiddleware(user, pass string) func(http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			u, p, ok := r.BasicAuth()
-			if !ok || u != user || p != pass {
-				w.Header().Set("WWW-Authenticate", "Basic realm=restricted")
-				http.Error(w, "unauthorized", http.StatusUnauthorized)
-				return
-			}
-			next.ServeHTTP(w, r)
-		})
-	}
-}
-
 func (s *Server) rateLimitMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		command := peekControlCommand(r)
 		if !s.controlLimiter.Allow() {
 			atomic.AddUint64(&s.controlDenied, 1)
+			s.metrics.ObserveControlCommand(command, "denied")
 			http.Error(w, "too many requests", http.StatusTooManyRequests)
 			return
 		}
 		atomic.AddUint64(&s.controlAllowed, 1)
+		s.metrics.ObserveControlCommand(command, "allowed")
 		next.ServeHTTP(w, r)
 	})
 }
 
+// peekControlCommand reads the "command" field out of a /api/control
+// request body for metrics labeling, then restores the body so the
+// downstream api.Control handler can still decode it in full.
+func peekControlCommand(r *http.Request) string {
+	if r.Body == nil {
+		return "unknown"
+	}
+	data, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		return "unknown"
+	}
+	var peek struct {
+		Command string `json:"command"`
+	}
+	if err := json.Unmarshal(data, &peek); err != nil {
+		return "unknown"
+	}
+	command := strings.TrimSpace(peek.Command)
+	if _, ok := knownControlCommands[command]; !ok {
+		return "other"
+	}
+	return command
+}
+
+// knownControlCommands mirrors the commands GRPCGateway.Execute switches
+// on. peekControlCommand maps anything else to "other" so an authenticated
+// caller can't grow the control_commands_total label set without bound by
+// posting arbitrary command strings.
+var knownControlCommands = map[string]struct{}{
+	"assign-role":  {},
+	"update-trust": {},
+	"schedule":     {},
+}
+
+// newStorage builds the Storage driver Server.controller records control
+// decisions through, selected by cfg.StorageDriver. An empty or "memory"
+// driver is what Dev mode and most tests want; "etcd" is for a real
+// multi-instance orchestrator deployment.
+func newStorage(cfg Config) (storage.Storage, error) {
+	switch cfg.StorageDriver {
+	case "", "memory":
+		return storage.NewMemoryStorage(), nil
+	case "etcd":
+		if len(cfg.StorageEndpoints) == 0 {
+			return nil, fmt.Errorf("storage_endpoints is required for the etcd storage driver")
+		}
+		return storage.NewEtcdStorage(context.Background(), cfg.StoragePrefix, storage.EtcdOptions{
+			Endpoints: cfg.StorageEndpoints,
+			TLS:       cfg.StorageTLS,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.StorageDriver)
+	}
+}
+
 func buildTLSConfig(certFile, keyFile, clientCA string) (*tls.Config, error) {
 	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
 	if err != nil {
@@ -1,7 +1,7 @@
 // CLASSIFICATION: COMMUNITY
-// Filename: server_test.go v0.2
+// Filename: server_test.go v0.4
 // Author: Lukas Bower
-// Date Modified: 2029-02-21
+// Date Modified: 2026-07-26
 // License: SPDX-License-Identifier: MIT OR Apache-2.0
 
 package http_test
@@ -13,6 +13,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -36,7 +38,7 @@ func newTestGateway() *testGateway {
 			QueenId:        "queen-primary",
 			GeneratedAt:    42,
 			TimeoutSeconds: 5,
-			Workers: []*rpc.WorkerStatus{
+			Workers: []*rpc.WorkerState{
 				{
 					WorkerId:     "worker-a",
 					Role:         "DroneWorker",
@@ -235,6 +237,100 @@ func TestMetricsEndpoint(t *testing.T) {
 	}
 }
 
+func TestMetricsEndpointPrometheusFormat(t *testing.T) {
+	router, _ := newRouter(t, "")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+	if _, err := http.Get(ts.URL + "/api/status"); err != nil {
+		t.Fatalf("get status: %v", err)
+	}
+	resp, err := http.Get(ts.URL + "/api/metrics?format=prometheus")
+	if err != nil {
+		t.Fatalf("get metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	buf := new(bytes.Buffer)
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	body := buf.String()
+	if !strings.Contains(body, "cohesix_http_requests_total") {
+		t.Fatalf("missing request counter in exposition:\n%s", body)
+	}
+	if !strings.Contains(body, `route="/api/status"`) {
+		t.Fatalf("missing route label in exposition:\n%s", body)
+	}
+}
+
+func TestMetricsEndpointNegotiatesPrometheusViaAccept(t *testing.T) {
+	router, _ := newRouter(t, "")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/api/metrics", nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Accept", "text/plain;version=0.0.4")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("get metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	buf := new(bytes.Buffer)
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "# HELP") {
+		t.Fatalf("expected prometheus exposition format, got:\n%s", buf.String())
+	}
+}
+
+func TestMetricsReportsControlCommandCounters(t *testing.T) {
+	cfg := orch.Config{
+		StaticDir:    "../../../static",
+		AuthUser:     "user",
+		AuthPass:     "pass",
+		AllowedRoles: []string{"QueenPrimary"},
+	}
+	gateway := newTestGateway()
+	cfg.Controller = gateway
+	cfg.ClusterClient = gateway
+	srv, err := orch.New(cfg)
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	ts := httptest.NewServer(srv.Router())
+	defer ts.Close()
+
+	buf := bytes.NewBufferString(`{"command":"assign-role","worker_id":"worker-a","role":"QueenPrimary"}`)
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/control", buf)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	req.SetBasicAuth("user", "pass")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	metricsResp, err := http.Get(ts.URL + "/api/metrics?format=prometheus")
+	if err != nil {
+		t.Fatalf("metrics: %v", err)
+	}
+	defer metricsResp.Body.Close()
+	body := new(bytes.Buffer)
+	if _, err := body.ReadFrom(metricsResp.Body); err != nil {
+		t.Fatalf("read metrics: %v", err)
+	}
+	if !strings.Contains(body.String(), `cohesix_control_commands_total{command="assign-role",result="allowed"} 1`) {
+		t.Fatalf("missing control command counter:\n%s", body.String())
+	}
+}
+
 func TestMetricsReportRateLimitCounters(t *testing.T) {
 	cfg := orch.Config{
 		StaticDir:    "../../../static",
@@ -334,6 +430,34 @@ func TestAccessLogging(t *testing.T) {
 	}
 }
 
+// TestStreamingRouteSurvivesMiddlewareStack is a regression test for two
+// independently-introduced bugs: statusRecorder (wrapping every request for
+// requestCounter) and accesslog's responseWriter (wrapping every request
+// when LogFile is set) each only forwarded Write/WriteHeader, so
+// serveStatusStreamSSE's http.Flusher type assertion failed and it wrote a
+// 500 instead of streaming. It runs the same streaming GET both with and
+// without LogFile set, since the two wrappers are installed independently
+// and either one regressing would have broken this.
+func TestStreamingRouteSurvivesMiddlewareStack(t *testing.T) {
+	for _, logPath := range []string{"", filepath.Join(t.TempDir(), "access.log")} {
+		router, _ := newRouter(t, logPath)
+		ts := httptest.NewServer(router)
+		defer ts.Close()
+
+		resp, err := http.Get(ts.URL + "/api/status/stream")
+		if err != nil {
+			t.Fatalf("get status stream (logPath=%q): %v", logPath, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("logPath=%q: expected 200, got %d", logPath, resp.StatusCode)
+		}
+		if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+			t.Fatalf("logPath=%q: expected text/event-stream, got %q", logPath, ct)
+		}
+	}
+}
+
 func TestDevModeDisablesAuth(t *testing.T) {
 	cfg := orch.Config{StaticDir: "../../../static", Dev: true, AuthUser: "a", AuthPass: "b"}
 	gateway := newTestGateway()
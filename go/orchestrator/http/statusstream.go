@@ -0,0 +1,137 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: statusstream.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/websocket"
+
+	"cohesix/internal/logging"
+	"cohesix/internal/orchestrator/api"
+)
+
+// statusStreamUpgrader upgrades /api/status/stream to WebSocket. Unlike
+// wsUpgrader in stream.go, it negotiates no subprotocol: status events are
+// always plain JSON, never the protobuf cluster-state frames /api/stream
+// can also serve.
+var statusStreamUpgrader = websocket.Upgrader{}
+
+// statusStreamHandler serves /api/status/stream, diffed worker/queen delta
+// events (see api.StatusBroadcaster) pushed to the caller as Server-Sent
+// Events, or over a WebSocket if the request asks to upgrade. This lets
+// dashboards and CLI tools (`cohesix watch`) avoid polling /api/status on
+// an interval the way the GUI's cluster-state WebSocket bridge already
+// avoids polling for the raw snapshot.
+func (s *Server) statusStreamHandler(w http.ResponseWriter, r *http.Request) {
+	if s.statusBroadcaster == nil {
+		http.Error(w, "status stream unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	lastEventID := parseLastEventID(r)
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		s.serveStatusStreamWS(w, r, lastEventID)
+		return
+	}
+	s.serveStatusStreamSSE(w, r, lastEventID)
+}
+
+// parseLastEventID reads the client's resume point from the standard
+// Last-Event-ID header (what EventSource sends on reconnect) or, failing
+// that, a last_event_id query parameter so a WebSocket client -- which has
+// no equivalent header -- can resume the same way. A missing or malformed
+// value resumes from the current tip.
+func parseLastEventID(r *http.Request) uint64 {
+	raw := r.Header.Get("Last-Event-ID")
+	if raw == "" {
+		raw = r.URL.Query().Get("last_event_id")
+	}
+	id, _ := strconv.ParseUint(raw, 10, 64)
+	return id
+}
+
+func (s *Server) serveStatusStreamSSE(w http.ResponseWriter, r *http.Request, lastEventID uint64) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	sub := s.statusBroadcaster.Subscribe(lastEventID)
+	defer s.statusBroadcaster.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ctx := r.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			if err := writeSSEEvent(w, event); err != nil {
+				streamLog.Warn("write status stream event failed", "error", err, logging.FieldRemoteAddr, r.RemoteAddr)
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeSSEEvent renders event in the standard "id:/event:/data:" SSE
+// framing, JSON-encoding just event.Data so a client's event listener gets
+// the same typed payload shape regardless of transport.
+func writeSSEEvent(w io.Writer, event api.StatusEvent) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+func (s *Server) serveStatusStreamWS(w http.ResponseWriter, r *http.Request, lastEventID uint64) {
+	conn, err := statusStreamUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		streamLog.Warn("status stream websocket upgrade failed", "error", err, logging.FieldRemoteAddr, r.RemoteAddr)
+		return
+	}
+	defer conn.Close()
+
+	sub := s.statusBroadcaster.Subscribe(lastEventID)
+	defer s.statusBroadcaster.Unsubscribe(sub)
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	go discardClientFrames(conn, cancel)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			if err := conn.WriteJSON(event); err != nil {
+				streamLog.Warn("write status stream frame failed", "error", err, logging.FieldRemoteAddr, r.RemoteAddr)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: statusstream_test.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package http_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"cohesix/internal/orchestrator/api"
+	orch "cohesix/internal/orchestrator/http"
+	"cohesix/internal/orchestrator/rpc"
+)
+
+// mutableStateGateway lets a test swap the cluster state FetchClusterState
+// returns while a StatusBroadcaster is concurrently polling it, guarding
+// the state pointer with a mutex so the swap is race-free.
+type mutableStateGateway struct {
+	*testGateway
+	mu    sync.Mutex
+	state *rpc.ClusterStateResponse
+}
+
+func newMutableStateGateway(gateway *testGateway) *mutableStateGateway {
+	return &mutableStateGateway{testGateway: gateway, state: gateway.state}
+}
+
+func (g *mutableStateGateway) FetchClusterState(ctx context.Context) (*rpc.ClusterStateResponse, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.state, nil
+}
+
+func (g *mutableStateGateway) setState(state *rpc.ClusterStateResponse) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.state = state
+}
+
+func (g *mutableStateGateway) getState() *rpc.ClusterStateResponse {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.state
+}
+
+// newStatusStreamServer starts srv.Start in the background so its
+// StatusBroadcaster actually polls -- httptest.NewServer alone only wires
+// up the router, the same way TestServerStart exercises the real listener
+// path -- while serving test traffic through an httptest.Server for the
+// stable loopback URL the other orchestrator/http tests use.
+func newStatusStreamServer(t *testing.T, gateway *mutableStateGateway) *httptest.Server {
+	t.Helper()
+	srv, err := orch.New(orch.Config{
+		StaticDir:                "../../../static",
+		Dev:                      true,
+		Port:                     0,
+		Controller:               gateway,
+		ClusterClient:            gateway,
+		StatusStreamTickInterval: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go srv.Start(ctx)
+	return httptest.NewServer(srv.Router())
+}
+
+func TestStatusStreamSSEDeliversWorkerAddedEvent(t *testing.T) {
+	gateway := newMutableStateGateway(newTestGateway())
+	ts := newStatusStreamServer(t, gateway)
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/api/status/stream", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("get status stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected text/event-stream, got %q", ct)
+	}
+
+	current := gateway.getState()
+	gateway.setState(&rpc.ClusterStateResponse{
+		QueenId: current.QueenId,
+		Workers: append(append([]*rpc.WorkerState{}, current.Workers...), &rpc.WorkerState{WorkerId: "worker-new"}),
+	})
+
+	reader := bufio.NewReader(resp.Body)
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read sse stream: %v", err)
+		}
+		if strings.HasPrefix(line, "event: "+string(api.EventWorkerAdded)) {
+			return
+		}
+	}
+	t.Fatal("timed out waiting for worker.added SSE event")
+}
+
+func TestStatusStreamWebSocketDeliversWorkerAddedEvent(t *testing.T) {
+	gateway := newMutableStateGateway(newTestGateway())
+	ts := newStatusStreamServer(t, gateway)
+	defer ts.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/api/status/stream"
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("dial websocket: %v", err)
+	}
+	defer conn.Close()
+
+	current := gateway.getState()
+	gateway.setState(&rpc.ClusterStateResponse{
+		QueenId: current.QueenId,
+		Workers: append(append([]*rpc.WorkerState{}, current.Workers...), &rpc.WorkerState{WorkerId: "worker-new"}),
+	})
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var event struct {
+		Type string `json:"type"`
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if err := conn.ReadJSON(&event); err != nil {
+			t.Fatalf("read websocket event: %v", err)
+		}
+		if event.Type == string(api.EventWorkerAdded) {
+			return
+		}
+	}
+	t.Fatal("timed out waiting for worker.added websocket event")
+}
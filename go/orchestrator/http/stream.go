@@ -0,0 +1,207 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: stream.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package http
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/proto"
+
+	"cohesix/internal/logging"
+	"cohesix/internal/orchestrator/api"
+	"cohesix/internal/orchestrator/rpc"
+)
+
+const (
+	// defaultWSMaxMessageBytes is the per-message buffer /api/stream allows
+	// when neither Config.StreamMaxMessageBytes nor the deprecated
+	// WSMaxMessageBytes is set. Naive grpc-websocket bridges default to 64
+	// KiB, which silently truncates a cluster snapshot once worker count or
+	// GPU telemetry grows past it; 4 MiB comfortably covers a full
+	// ClusterStateResponse with headroom.
+	defaultWSMaxMessageBytes = 4 << 20 // 4 MiB
+
+	// wsOutboundQueueSize bounds how many undelivered cluster state
+	// messages are buffered per connection before the oldest is dropped.
+	wsOutboundQueueSize = 8
+
+	// wsHeartbeatInterval is how often a ping keeps an idle connection (and
+	// any intermediating proxy/load balancer) from timing it out.
+	wsHeartbeatInterval = 20 * time.Second
+	wsPingTimeout       = 5 * time.Second
+
+	// protoSubprotocol/jsonSubprotocol are the Sec-WebSocket-Protocol
+	// values a client negotiates to pick the frame encoding; jsonSubprotocol
+	// is also what a client gets if it names no subprotocol at all.
+	protoSubprotocol = "cohesix.v1+proto"
+	jsonSubprotocol  = "cohesix.v1+json"
+)
+
+var streamLog = logging.New("orchestrator.cluster_stream")
+
+var wsUpgrader = websocket.Upgrader{
+	Subprotocols: []string{protoSubprotocol, jsonSubprotocol},
+}
+
+// clusterStreamHandler upgrades /api/stream (and its /api/cluster/stream
+// alias) to WebSocket and bridges the orchestrator's WatchClusterState
+// server-streaming RPC to the browser, frame by frame. It replaces the
+// GUI's previous approach of polling /api/status on an interval, at the
+// cost of needing its own framing and backpressure handling that plain
+// unary HTTP never required.
+func (s *Server) clusterStreamHandler(w http.ResponseWriter, r *http.Request) {
+	watcher, ok := s.clusterClient.(api.ClusterStateWatcher)
+	if !ok {
+		http.Error(w, "cluster state streaming unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		streamLog.Warn("websocket upgrade failed", "error", err, logging.FieldRemoteAddr, r.RemoteAddr)
+		return
+	}
+	defer conn.Close()
+
+	maxBytes := s.cfg.StreamMaxMessageBytes
+	if maxBytes <= 0 {
+		maxBytes = s.cfg.WSMaxMessageBytes
+	}
+	if maxBytes <= 0 {
+		maxBytes = defaultWSMaxMessageBytes
+	}
+	conn.SetReadLimit(int64(maxBytes))
+	useProto := conn.Subprotocol() == protoSubprotocol
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	stream, err := watcher.WatchClusterState(ctx)
+	if err != nil {
+		streamLog.Warn("watch cluster state failed", "error", err, logging.FieldRemoteAddr, r.RemoteAddr)
+		conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseInternalServerErr, err.Error()))
+		return
+	}
+
+	s.metrics.WSConnectionOpened()
+	defer s.metrics.WSConnectionClosed()
+
+	out := make(chan *rpc.ClusterStateResponse, wsOutboundQueueSize)
+	go s.pumpClusterState(ctx, cancel, stream, out)
+	go discardClientFrames(conn, cancel)
+
+	log := streamLog.With(logging.FieldRemoteAddr, r.RemoteAddr)
+	heartbeat := time.NewTicker(wsHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case state, ok := <-out:
+			if !ok {
+				return
+			}
+			frame, err := encodeClusterState(state, useProto)
+			if err != nil {
+				log.Error("encode cluster state failed", "error", err)
+				continue
+			}
+			if len(frame) > maxBytes {
+				s.metrics.ObserveWSDropped("oversized")
+				log.Warn("dropping oversized cluster state frame", "bytes", len(frame), "limit", maxBytes)
+				continue
+			}
+			if err := conn.WriteMessage(websocket.BinaryMessage, frame); err != nil {
+				log.Warn("write cluster state frame failed", "error", err)
+				return
+			}
+		case <-heartbeat.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsPingTimeout)); err != nil {
+				log.Warn("ping failed", "error", err)
+				return
+			}
+		}
+	}
+}
+
+// pumpClusterState drains the WatchClusterState gRPC stream into out,
+// applying drop-oldest backpressure: a browser client that falls behind
+// loses the stalest snapshot rather than the bridge buffering unbounded
+// memory or stalling the gRPC stream's own flow control.
+func (s *Server) pumpClusterState(ctx context.Context, cancel context.CancelFunc, stream rpc.OrchestratorService_WatchClusterStateClient, out chan *rpc.ClusterStateResponse) {
+	defer cancel()
+	defer close(out)
+	for {
+		state, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() == nil {
+				streamLog.Warn("cluster state stream ended", "error", err)
+			}
+			return
+		}
+		select {
+		case out <- state:
+			continue
+		default:
+		}
+		select {
+		case <-out:
+			s.metrics.ObserveWSDropped("backpressure")
+		default:
+		}
+		select {
+		case out <- state:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// discardClientFrames keeps reading (and discarding) inbound frames so
+// gorilla's control-frame handling (pong, close) keeps firing; the stream
+// is one-way from the caller's perspective, so any payload a client sends
+// is intentionally ignored.
+func discardClientFrames(conn *websocket.Conn, cancel context.CancelFunc) {
+	defer cancel()
+	for {
+		if _, _, err := conn.NextReader(); err != nil {
+			return
+		}
+	}
+}
+
+// encodeClusterState marshals state as protobuf when the client negotiated
+// the cohesix.v1+proto subprotocol, or otherwise as the same JSON shape
+// /api/status serves (via api.BuildStatusResponse), then prefixes the
+// result with a 4-byte big-endian length. The WebSocket message boundary
+// already frames the payload, but the explicit length lets a client that
+// buffers multiple frames together (or replays a capture) find boundaries
+// without depending on that transport detail.
+func encodeClusterState(state *rpc.ClusterStateResponse, useProto bool) ([]byte, error) {
+	var (
+		payload []byte
+		err     error
+	)
+	if useProto {
+		payload, err = proto.Marshal(state)
+	} else {
+		payload, err = json.Marshal(api.BuildStatusResponse(state))
+	}
+	if err != nil {
+		return nil, err
+	}
+	frame := make([]byte, 4+len(payload))
+	binary.BigEndian.PutUint32(frame[:4], uint32(len(payload)))
+	copy(frame[4:], payload)
+	return frame, nil
+}
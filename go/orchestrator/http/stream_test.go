@@ -0,0 +1,164 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: stream_test.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package http_test
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/grpc"
+
+	"cohesix/internal/orchestrator/api"
+	orch "cohesix/internal/orchestrator/http"
+	"cohesix/internal/orchestrator/rpc"
+)
+
+// fakeWatchStream implements rpc.OrchestratorService_WatchClusterStateClient
+// by embedding a nil grpc.ClientStream (the bridge only calls Recv) and
+// replaying a fixed sequence of states before blocking until ctx is
+// cancelled, the way a real stream blocks once there's nothing new to
+// report.
+type fakeWatchStream struct {
+	grpc.ClientStream
+	ctx    context.Context
+	states []*rpc.ClusterStateResponse
+	idx    int
+}
+
+func (f *fakeWatchStream) Recv() (*rpc.ClusterStateResponse, error) {
+	if f.idx < len(f.states) {
+		s := f.states[f.idx]
+		f.idx++
+		return s, nil
+	}
+	<-f.ctx.Done()
+	return nil, f.ctx.Err()
+}
+
+// streamingGateway adds a WatchClusterState implementation on top of
+// testGateway so it satisfies api.ClusterStateWatcher too.
+type streamingGateway struct {
+	*testGateway
+	states []*rpc.ClusterStateResponse
+}
+
+func (g *streamingGateway) WatchClusterState(ctx context.Context) (rpc.OrchestratorService_WatchClusterStateClient, error) {
+	return &fakeWatchStream{ctx: ctx, states: g.states}, nil
+}
+
+func TestClusterStreamDeliversLengthFramedJSON(t *testing.T) {
+	gateway := &streamingGateway{
+		testGateway: newTestGateway(),
+		states: []*rpc.ClusterStateResponse{
+			{QueenId: "queen-1", GeneratedAt: 1},
+			{QueenId: "queen-2", GeneratedAt: 2},
+		},
+	}
+	srv, err := orch.New(orch.Config{
+		Dev:           true,
+		StaticDir:     "../../../static",
+		Controller:    gateway,
+		ClusterClient: gateway,
+	})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	ts := httptest.NewServer(srv.Router())
+	defer ts.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/api/cluster/stream"
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	for i, want := range gateway.states {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("read frame %d: %v", i, err)
+		}
+		if len(data) < 4 {
+			t.Fatalf("frame %d too short: %d bytes", i, len(data))
+		}
+		n := binary.BigEndian.Uint32(data[:4])
+		if int(n) != len(data)-4 {
+			t.Fatalf("frame %d length prefix %d != payload %d bytes", i, n, len(data)-4)
+		}
+		var got api.StatusResponse
+		if err := json.Unmarshal(data[4:], &got); err != nil {
+			t.Fatalf("unmarshal frame %d: %v", i, err)
+		}
+		if got.QueenID != want.QueenId {
+			t.Fatalf("frame %d queen_id = %q, want %q", i, got.QueenID, want.QueenId)
+		}
+	}
+}
+
+func TestClusterStreamServesAPIStreamAlias(t *testing.T) {
+	gateway := &streamingGateway{
+		testGateway: newTestGateway(),
+		states: []*rpc.ClusterStateResponse{
+			{QueenId: "queen-1", GeneratedAt: 1},
+		},
+	}
+	srv, err := orch.New(orch.Config{
+		Dev:                   true,
+		StaticDir:             "../../../static",
+		Controller:            gateway,
+		ClusterClient:         gateway,
+		StreamMaxMessageBytes: 1 << 20,
+	})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	ts := httptest.NewServer(srv.Router())
+	defer ts.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/api/stream"
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if _, _, err := conn.ReadMessage(); err != nil {
+		t.Fatalf("read frame: %v", err)
+	}
+}
+
+func TestClusterStreamUnavailableWithoutWatcher(t *testing.T) {
+	gateway := newTestGateway()
+	srv, err := orch.New(orch.Config{
+		Dev:           true,
+		StaticDir:     "../../../static",
+		Controller:    gateway,
+		ClusterClient: gateway,
+	})
+	if err != nil {
+		t.Fatalf("new server: %v", err)
+	}
+	ts := httptest.NewServer(srv.Router())
+	defer ts.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/api/cluster/stream"
+	_, resp, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err == nil {
+		t.Fatalf("expected dial to fail when the controller can't stream")
+	}
+	if resp == nil || resp.StatusCode != 503 {
+		t.Fatalf("expected 503, got %+v", resp)
+	}
+}
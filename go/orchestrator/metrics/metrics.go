@@ -0,0 +1,169 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: metrics.go v0.2
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+// Package metrics defines the Prometheus collectors the orchestrator HTTP
+// server exposes at /api/metrics, built on
+// github.com/prometheus/client_golang. It replaces the ad-hoc JSON
+// counters that used to live directly on http.Server with proper
+// CounterVec/HistogramVec collectors that requestCounter and
+// rateLimitMiddleware can record observations into.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "cohesix"
+
+// DefaultBuckets are the http_request_duration_seconds histogram buckets
+// New uses when Config.MetricsBuckets is empty -- four buckets, coarser
+// than prometheus.DefBuckets, starting at 0.1s rather than 5ms and topping
+// out at 5s rather than 10s.
+var DefaultBuckets = []float64{0.1, 0.3, 1.2, 5}
+
+// Registry holds the collectors registered for one Server. Each Server
+// owns its own Registry, built on a private prometheus.Registry rather
+// than prometheus.DefaultRegisterer, so multiple Servers in one process
+// (as in tests) don't collide registering the same metric names.
+type Registry struct {
+	reg *prometheus.Registry
+
+	requestsTotal   *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	controlCommands *prometheus.CounterVec
+	controlAllowed  prometheus.Counter
+	controlDenied   prometheus.Counter
+	activeSessions  prometheus.Gauge
+	wsConnections   prometheus.Gauge
+	wsDropped       *prometheus.CounterVec
+}
+
+// New creates and registers the orchestrator's metric collectors. buckets
+// configures the http_request_duration_seconds histogram; a nil or empty
+// slice falls back to DefaultBuckets.
+func New(buckets []float64) *Registry {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	r := &Registry{
+		reg: prometheus.NewRegistry(),
+		requestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Total HTTP requests handled by the orchestrator, by route, method, and code.",
+		}, []string{"route", "method", "code"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency in seconds, by route, method, and code.",
+			Buckets:   buckets,
+		}, []string{"route", "method", "code"}),
+		controlCommands: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "control_commands_total",
+			Help:      "Total /api/control commands accepted or rejected by the rate limiter, by command and result.",
+		}, []string{"command", "result"}),
+		controlAllowed: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "control_allowed_total",
+			Help:      "Total /api/control requests let through the rate limiter, across all commands.",
+		}),
+		controlDenied: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "control_denied_total",
+			Help:      "Total /api/control requests rejected by the rate limiter, across all commands.",
+		}),
+		activeSessions: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "active_sessions",
+			Help:      "Current number of open HTTP connections to the orchestrator.",
+		}),
+		wsConnections: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "stream_active_connections",
+			Help:      "Current number of open /api/stream WebSocket connections.",
+		}),
+		wsDropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "stream_frames_dropped_total",
+			Help:      "Total cluster state frames dropped from the /api/stream outbound queue under backpressure or oversize, by reason.",
+		}, []string{"reason"}),
+	}
+	r.reg.MustRegister(
+		r.requestsTotal, r.requestDuration, r.controlCommands, r.controlAllowed, r.controlDenied,
+		r.activeSessions, r.wsConnections, r.wsDropped,
+	)
+	return r
+}
+
+// ObserveRequest records one completed HTTP request's route, method,
+// status code, and latency.
+func (r *Registry) ObserveRequest(route, method string, status int, elapsed time.Duration) {
+	labels := prometheus.Labels{"route": route, "method": method, "code": strconv.Itoa(status)}
+	r.requestsTotal.With(labels).Inc()
+	r.requestDuration.With(labels).Observe(elapsed.Seconds())
+}
+
+// ObserveControlCommand records one rate-limiter decision for a
+// /api/control command ("allowed" or "denied"), both against the
+// per-command control_commands_total breakdown and the aggregate
+// control_allowed_total/control_denied_total counters.
+func (r *Registry) ObserveControlCommand(command, result string) {
+	r.controlCommands.WithLabelValues(command, result).Inc()
+	switch result {
+	case "allowed":
+		r.controlAllowed.Inc()
+	case "denied":
+		r.controlDenied.Inc()
+	}
+}
+
+// SessionOpened/SessionClosed track the number of live HTTP connections
+// for the active_sessions gauge.
+func (r *Registry) SessionOpened() {
+	r.activeSessions.Inc()
+}
+
+func (r *Registry) SessionClosed() {
+	r.activeSessions.Dec()
+}
+
+// WSConnectionOpened/WSConnectionClosed track the number of live
+// /api/stream connections for the stream_active_connections gauge.
+func (r *Registry) WSConnectionOpened() {
+	r.wsConnections.Inc()
+}
+
+func (r *Registry) WSConnectionClosed() {
+	r.wsConnections.Dec()
+}
+
+// ObserveWSDropped records one cluster state frame dropped from a
+// /api/stream outbound queue, either to backpressure or because it
+// exceeded StreamMaxMessageBytes.
+func (r *Registry) ObserveWSDropped(reason string) {
+	r.wsDropped.WithLabelValues(reason).Inc()
+}
+
+// Handler returns the Prometheus text-exposition handler for this
+// registry, suitable for mounting directly or delegating to from a
+// content-negotiating handler.
+func (r *Registry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.reg, promhttp.HandlerOpts{})
+}
+
+// Register adds additional collectors (e.g. api.MetricsRegistry's
+// worker/GPU telemetry) to this Registry's /metrics exposition, so callers
+// outside this package can extend what one scrape returns without New
+// having to know about every collector up front.
+func (r *Registry) Register(collectors ...prometheus.Collector) {
+	r.reg.MustRegister(collectors...)
+}
@@ -0,0 +1,106 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: metrics_test.go v0.2
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestObserveRequestExposedAsPrometheusText(t *testing.T) {
+	r := New(nil)
+	r.ObserveRequest("/api/status", "GET", 200, 15*time.Millisecond)
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, req)
+
+	body := rec.Body.String()
+	if !strings.Contains(body, `cohesix_http_requests_total{code="200",method="GET",route="/api/status"} 1`) {
+		t.Fatalf("missing request counter in exposition:\n%s", body)
+	}
+	if !strings.Contains(body, "cohesix_http_request_duration_seconds_bucket") {
+		t.Fatalf("missing latency histogram in exposition:\n%s", body)
+	}
+}
+
+func TestObserveControlCommandExposedAsPrometheusText(t *testing.T) {
+	r := New(nil)
+	r.ObserveControlCommand("assign-role", "allowed")
+	r.ObserveControlCommand("assign-role", "denied")
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, req)
+
+	body := rec.Body.String()
+	if !strings.Contains(body, `cohesix_control_commands_total{command="assign-role",result="allowed"} 1`) {
+		t.Fatalf("missing allowed counter in exposition:\n%s", body)
+	}
+	if !strings.Contains(body, `cohesix_control_commands_total{command="assign-role",result="denied"} 1`) {
+		t.Fatalf("missing denied counter in exposition:\n%s", body)
+	}
+	if !strings.Contains(body, "cohesix_control_allowed_total 1") {
+		t.Fatalf("missing aggregate allowed counter in exposition:\n%s", body)
+	}
+	if !strings.Contains(body, "cohesix_control_denied_total 1") {
+		t.Fatalf("missing aggregate denied counter in exposition:\n%s", body)
+	}
+}
+
+func TestActiveSessionsExposedAsPrometheusText(t *testing.T) {
+	r := New(nil)
+	r.SessionOpened()
+	r.SessionOpened()
+	r.SessionClosed()
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, req)
+
+	body := rec.Body.String()
+	if !strings.Contains(body, "cohesix_active_sessions 1") {
+		t.Fatalf("missing active sessions gauge in exposition:\n%s", body)
+	}
+}
+
+func TestNewUsesConfiguredBuckets(t *testing.T) {
+	r := New([]float64{1, 2})
+	r.ObserveRequest("/api/status", "GET", 200, 500*time.Millisecond)
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, req)
+
+	body := rec.Body.String()
+	if !strings.Contains(body, `cohesix_http_request_duration_seconds_bucket{code="200",method="GET",route="/api/status",le="1"}`) {
+		t.Fatalf("missing configured bucket in exposition:\n%s", body)
+	}
+	if strings.Contains(body, `le="0.1"`) {
+		t.Fatalf("default bucket leaked in despite configured buckets:\n%s", body)
+	}
+}
+
+func TestStreamMetricsExposedAsPrometheusText(t *testing.T) {
+	r := New(nil)
+	r.WSConnectionOpened()
+	r.ObserveWSDropped("backpressure")
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, req)
+
+	body := rec.Body.String()
+	if !strings.Contains(body, "cohesix_stream_active_connections 1") {
+		t.Fatalf("missing connections gauge in exposition:\n%s", body)
+	}
+	if !strings.Contains(body, `cohesix_stream_frames_dropped_total{reason="backpressure"} 1`) {
+		t.Fatalf("missing dropped counter in exposition:\n%s", body)
+	}
+}
@@ -0,0 +1,909 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: orchestrator.pb.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+// Package rpc holds the Go types for orchestrator.proto. There is no protoc
+// or buf binary in this build environment, so these are hand-written rather
+// than protoc-gen-go output -- but they follow the same structure protoc-gen-go
+// would produce (a protoreflect.FileDescriptor built from a raw, serialized
+// FileDescriptorProto, with protoimpl.TypeBuilder wiring each Go struct to
+// its message descriptor) so that proto.Marshal, the grpc wire codec, and
+// reflection-based tooling all see real, wire-compatible messages instead of
+// plain structs that merely happen to compile against the call sites below.
+package rpc
+
+import (
+	reflect "reflect"
+	sync "sync"
+
+	proto "google.golang.org/protobuf/proto"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type JoinRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JoinRequest) Reset() {
+	*x = JoinRequest{}
+	mi := &file_orchestrator_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinRequest) String() string { return protoimpl.X.MessageStringOf(x) }
+
+func (*JoinRequest) ProtoMessage() {}
+
+func (x *JoinRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *JoinRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *JoinRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type JoinResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Accepted      bool                   `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JoinResponse) Reset() {
+	*x = JoinResponse{}
+	mi := &file_orchestrator_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinResponse) String() string { return protoimpl.X.MessageStringOf(x) }
+
+func (*JoinResponse) ProtoMessage() {}
+
+func (x *JoinResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *JoinResponse) GetAccepted() bool {
+	if x != nil {
+		return x.Accepted
+	}
+	return false
+}
+
+type HeartbeatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeartbeatRequest) Reset() {
+	*x = HeartbeatRequest{}
+	mi := &file_orchestrator_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatRequest) String() string { return protoimpl.X.MessageStringOf(x) }
+
+func (*HeartbeatRequest) ProtoMessage() {}
+
+func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *HeartbeatRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+type HeartbeatResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ok            bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeartbeatResponse) Reset() {
+	*x = HeartbeatResponse{}
+	mi := &file_orchestrator_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatResponse) String() string { return protoimpl.X.MessageStringOf(x) }
+
+func (*HeartbeatResponse) ProtoMessage() {}
+
+func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *HeartbeatResponse) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+type AssignRoleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignRoleRequest) Reset() {
+	*x = AssignRoleRequest{}
+	mi := &file_orchestrator_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignRoleRequest) String() string { return protoimpl.X.MessageStringOf(x) }
+
+func (*AssignRoleRequest) ProtoMessage() {}
+
+func (x *AssignRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *AssignRoleRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *AssignRoleRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type AssignRoleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Updated       bool                   `protobuf:"varint,1,opt,name=updated,proto3" json:"updated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignRoleResponse) Reset() {
+	*x = AssignRoleResponse{}
+	mi := &file_orchestrator_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignRoleResponse) String() string { return protoimpl.X.MessageStringOf(x) }
+
+func (*AssignRoleResponse) ProtoMessage() {}
+
+func (x *AssignRoleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *AssignRoleResponse) GetUpdated() bool {
+	if x != nil {
+		return x.Updated
+	}
+	return false
+}
+
+type TrustUpdateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Level         string                 `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrustUpdateRequest) Reset() {
+	*x = TrustUpdateRequest{}
+	mi := &file_orchestrator_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrustUpdateRequest) String() string { return protoimpl.X.MessageStringOf(x) }
+
+func (*TrustUpdateRequest) ProtoMessage() {}
+
+func (x *TrustUpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *TrustUpdateRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *TrustUpdateRequest) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+type TrustUpdateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrustUpdateResponse) Reset() {
+	*x = TrustUpdateResponse{}
+	mi := &file_orchestrator_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrustUpdateResponse) String() string { return protoimpl.X.MessageStringOf(x) }
+
+func (*TrustUpdateResponse) ProtoMessage() {}
+
+func (x *TrustUpdateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+type ScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	RequireGpu    bool                   `protobuf:"varint,2,opt,name=require_gpu,json=requireGpu,proto3" json:"require_gpu,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduleRequest) Reset() {
+	*x = ScheduleRequest{}
+	mi := &file_orchestrator_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleRequest) String() string { return protoimpl.X.MessageStringOf(x) }
+
+func (*ScheduleRequest) ProtoMessage() {}
+
+func (x *ScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *ScheduleRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *ScheduleRequest) GetRequireGpu() bool {
+	if x != nil {
+		return x.RequireGpu
+	}
+	return false
+}
+
+type ScheduleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduleResponse) Reset() {
+	*x = ScheduleResponse{}
+	mi := &file_orchestrator_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleResponse) String() string { return protoimpl.X.MessageStringOf(x) }
+
+func (*ScheduleResponse) ProtoMessage() {}
+
+func (x *ScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+type ClusterStateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClusterStateRequest) Reset() {
+	*x = ClusterStateRequest{}
+	mi := &file_orchestrator_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClusterStateRequest) String() string { return protoimpl.X.MessageStringOf(x) }
+
+func (*ClusterStateRequest) ProtoMessage() {}
+
+func (x *ClusterStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+type ClusterStateResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	QueenId        string                 `protobuf:"bytes,1,opt,name=queen_id,json=queenId,proto3" json:"queen_id,omitempty"`
+	GeneratedAt    uint64                 `protobuf:"varint,2,opt,name=generated_at,json=generatedAt,proto3" json:"generated_at,omitempty"`
+	TimeoutSeconds uint32                 `protobuf:"varint,3,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"`
+	Workers        []*WorkerState         `protobuf:"bytes,4,rep,name=workers,proto3" json:"workers,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ClusterStateResponse) Reset() {
+	*x = ClusterStateResponse{}
+	mi := &file_orchestrator_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClusterStateResponse) String() string { return protoimpl.X.MessageStringOf(x) }
+
+func (*ClusterStateResponse) ProtoMessage() {}
+
+func (x *ClusterStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *ClusterStateResponse) GetQueenId() string {
+	if x != nil {
+		return x.QueenId
+	}
+	return ""
+}
+
+func (x *ClusterStateResponse) GetGeneratedAt() uint64 {
+	if x != nil {
+		return x.GeneratedAt
+	}
+	return 0
+}
+
+func (x *ClusterStateResponse) GetTimeoutSeconds() uint32 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+func (x *ClusterStateResponse) GetWorkers() []*WorkerState {
+	if x != nil {
+		return x.Workers
+	}
+	return nil
+}
+
+// WorkerState mirrors a single worker's membership, role, trust and GPU
+// telemetry as tracked by the orchestrator's cluster state.
+type WorkerState struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Ip            string                 `protobuf:"bytes,4,opt,name=ip,proto3" json:"ip,omitempty"`
+	Trust         string                 `protobuf:"bytes,5,opt,name=trust,proto3" json:"trust,omitempty"`
+	BootTs        uint64                 `protobuf:"varint,6,opt,name=boot_ts,json=bootTs,proto3" json:"boot_ts,omitempty"`
+	LastSeen      uint64                 `protobuf:"varint,7,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	Capabilities  []string               `protobuf:"bytes,8,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	Gpu           *GpuTelemetry          `protobuf:"bytes,9,opt,name=gpu,proto3" json:"gpu,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkerState) Reset() {
+	*x = WorkerState{}
+	mi := &file_orchestrator_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkerState) String() string { return protoimpl.X.MessageStringOf(x) }
+
+func (*WorkerState) ProtoMessage() {}
+
+func (x *WorkerState) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *WorkerState) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *WorkerState) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *WorkerState) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *WorkerState) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *WorkerState) GetTrust() string {
+	if x != nil {
+		return x.Trust
+	}
+	return ""
+}
+
+func (x *WorkerState) GetBootTs() uint64 {
+	if x != nil {
+		return x.BootTs
+	}
+	return 0
+}
+
+func (x *WorkerState) GetLastSeen() uint64 {
+	if x != nil {
+		return x.LastSeen
+	}
+	return 0
+}
+
+func (x *WorkerState) GetCapabilities() []string {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+func (x *WorkerState) GetGpu() *GpuTelemetry {
+	if x != nil {
+		return x.Gpu
+	}
+	return nil
+}
+
+type GpuTelemetry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PerfWatt      float32                `protobuf:"fixed32,1,opt,name=perf_watt,json=perfWatt,proto3" json:"perf_watt,omitempty"`
+	MemTotal      uint64                 `protobuf:"varint,2,opt,name=mem_total,json=memTotal,proto3" json:"mem_total,omitempty"`
+	MemFree       uint64                 `protobuf:"varint,3,opt,name=mem_free,json=memFree,proto3" json:"mem_free,omitempty"`
+	LastTemp      uint32                 `protobuf:"varint,4,opt,name=last_temp,json=lastTemp,proto3" json:"last_temp,omitempty"`
+	GpuCapacity   uint32                 `protobuf:"varint,5,opt,name=gpu_capacity,json=gpuCapacity,proto3" json:"gpu_capacity,omitempty"`
+	CurrentLoad   uint32                 `protobuf:"varint,6,opt,name=current_load,json=currentLoad,proto3" json:"current_load,omitempty"`
+	LatencyScore  uint32                 `protobuf:"varint,7,opt,name=latency_score,json=latencyScore,proto3" json:"latency_score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GpuTelemetry) Reset() {
+	*x = GpuTelemetry{}
+	mi := &file_orchestrator_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GpuTelemetry) String() string { return protoimpl.X.MessageStringOf(x) }
+
+func (*GpuTelemetry) ProtoMessage() {}
+
+func (x *GpuTelemetry) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *GpuTelemetry) GetPerfWatt() float32 {
+	if x != nil {
+		return x.PerfWatt
+	}
+	return 0
+}
+
+func (x *GpuTelemetry) GetMemTotal() uint64 {
+	if x != nil {
+		return x.MemTotal
+	}
+	return 0
+}
+
+func (x *GpuTelemetry) GetMemFree() uint64 {
+	if x != nil {
+		return x.MemFree
+	}
+	return 0
+}
+
+func (x *GpuTelemetry) GetLastTemp() uint32 {
+	if x != nil {
+		return x.LastTemp
+	}
+	return 0
+}
+
+func (x *GpuTelemetry) GetGpuCapacity() uint32 {
+	if x != nil {
+		return x.GpuCapacity
+	}
+	return 0
+}
+
+func (x *GpuTelemetry) GetCurrentLoad() uint32 {
+	if x != nil {
+		return x.CurrentLoad
+	}
+	return 0
+}
+
+func (x *GpuTelemetry) GetLatencyScore() uint32 {
+	if x != nil {
+		return x.LatencyScore
+	}
+	return 0
+}
+
+var File_orchestrator_proto protoreflect.FileDescriptor
+
+// file_orchestrator_proto_rawDesc is the serialized FileDescriptorProto for
+// orchestrator.proto. protoc would emit this as a byte-string literal
+// computed by parsing the .proto source; built here instead by constructing
+// the equivalent descriptorpb.FileDescriptorProto directly in Go and
+// marshaling it once at init, since no .proto parser is available.
+var file_orchestrator_proto_rawDesc = buildOrchestratorFileDescriptorProto()
+
+func buildOrchestratorFileDescriptorProto() []byte {
+	str := func(s string) *string { return &s }
+	i32 := func(i int32) *int32 { return &i }
+	label := func(l descriptorpb.FieldDescriptorProto_Label) *descriptorpb.FieldDescriptorProto_Label { return &l }
+	typ := func(t descriptorpb.FieldDescriptorProto_Type) *descriptorpb.FieldDescriptorProto_Type { return &t }
+
+	field := func(name string, number int32, t descriptorpb.FieldDescriptorProto_Type, repeated bool, jsonName string, typeName string) *descriptorpb.FieldDescriptorProto {
+		l := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+		if repeated {
+			l = descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+		}
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:     str(name),
+			Number:   i32(number),
+			Label:    label(l),
+			Type:     typ(t),
+			JsonName: str(jsonName),
+		}
+		if typeName != "" {
+			f.TypeName = str(typeName)
+		}
+		return f
+	}
+
+	message := func(name string, fields ...*descriptorpb.FieldDescriptorProto) *descriptorpb.DescriptorProto {
+		return &descriptorpb.DescriptorProto{Name: str(name), Field: fields}
+	}
+
+	method := func(name, input, output string, serverStreaming bool) *descriptorpb.MethodDescriptorProto {
+		m := &descriptorpb.MethodDescriptorProto{
+			Name:       str(name),
+			InputType:  str(input),
+			OutputType: str(output),
+		}
+		if serverStreaming {
+			m.ServerStreaming = protoHelperBool(true)
+		}
+		return m
+	}
+
+	const pkg = "cohesix.orchestrator"
+	qualify := func(name string) string { return "." + pkg + "." + name }
+
+	fd := &descriptorpb.FileDescriptorProto{
+		Name:    str("orchestrator.proto"),
+		Package: str(pkg),
+		Syntax:  str("proto3"),
+		Options: &descriptorpb.FileOptions{
+			GoPackage: str("cohesix/internal/orchestrator/rpc"),
+		},
+		MessageType: []*descriptorpb.DescriptorProto{
+			message("JoinRequest",
+				field("worker_id", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, false, "workerId", ""),
+				field("role", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, false, "role", ""),
+			),
+			message("JoinResponse",
+				field("accepted", 1, descriptorpb.FieldDescriptorProto_TYPE_BOOL, false, "accepted", ""),
+			),
+			message("HeartbeatRequest",
+				field("worker_id", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, false, "workerId", ""),
+			),
+			message("HeartbeatResponse",
+				field("ok", 1, descriptorpb.FieldDescriptorProto_TYPE_BOOL, false, "ok", ""),
+			),
+			message("AssignRoleRequest",
+				field("worker_id", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, false, "workerId", ""),
+				field("role", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, false, "role", ""),
+			),
+			message("AssignRoleResponse",
+				field("updated", 1, descriptorpb.FieldDescriptorProto_TYPE_BOOL, false, "updated", ""),
+			),
+			message("TrustUpdateRequest",
+				field("worker_id", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, false, "workerId", ""),
+				field("level", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, false, "level", ""),
+			),
+			message("TrustUpdateResponse"),
+			message("ScheduleRequest",
+				field("agent_id", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, false, "agentId", ""),
+				field("require_gpu", 2, descriptorpb.FieldDescriptorProto_TYPE_BOOL, false, "requireGpu", ""),
+			),
+			message("ScheduleResponse"),
+			message("ClusterStateRequest"),
+			message("ClusterStateResponse",
+				field("queen_id", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, false, "queenId", ""),
+				field("generated_at", 2, descriptorpb.FieldDescriptorProto_TYPE_UINT64, false, "generatedAt", ""),
+				field("timeout_seconds", 3, descriptorpb.FieldDescriptorProto_TYPE_UINT32, false, "timeoutSeconds", ""),
+				field("workers", 4, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, true, "workers", qualify("WorkerState")),
+			),
+			message("WorkerState",
+				field("worker_id", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, false, "workerId", ""),
+				field("role", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, false, "role", ""),
+				field("status", 3, descriptorpb.FieldDescriptorProto_TYPE_STRING, false, "status", ""),
+				field("ip", 4, descriptorpb.FieldDescriptorProto_TYPE_STRING, false, "ip", ""),
+				field("trust", 5, descriptorpb.FieldDescriptorProto_TYPE_STRING, false, "trust", ""),
+				field("boot_ts", 6, descriptorpb.FieldDescriptorProto_TYPE_UINT64, false, "bootTs", ""),
+				field("last_seen", 7, descriptorpb.FieldDescriptorProto_TYPE_UINT64, false, "lastSeen", ""),
+				field("capabilities", 8, descriptorpb.FieldDescriptorProto_TYPE_STRING, true, "capabilities", ""),
+				field("gpu", 9, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, false, "gpu", qualify("GpuTelemetry")),
+			),
+			message("GpuTelemetry",
+				field("perf_watt", 1, descriptorpb.FieldDescriptorProto_TYPE_FLOAT, false, "perfWatt", ""),
+				field("mem_total", 2, descriptorpb.FieldDescriptorProto_TYPE_UINT64, false, "memTotal", ""),
+				field("mem_free", 3, descriptorpb.FieldDescriptorProto_TYPE_UINT64, false, "memFree", ""),
+				field("last_temp", 4, descriptorpb.FieldDescriptorProto_TYPE_UINT32, false, "lastTemp", ""),
+				field("gpu_capacity", 5, descriptorpb.FieldDescriptorProto_TYPE_UINT32, false, "gpuCapacity", ""),
+				field("current_load", 6, descriptorpb.FieldDescriptorProto_TYPE_UINT32, false, "currentLoad", ""),
+				field("latency_score", 7, descriptorpb.FieldDescriptorProto_TYPE_UINT32, false, "latencyScore", ""),
+			),
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{
+			{
+				Name: str("OrchestratorService"),
+				Method: []*descriptorpb.MethodDescriptorProto{
+					method("Join", qualify("JoinRequest"), qualify("JoinResponse"), false),
+					method("Heartbeat", qualify("HeartbeatRequest"), qualify("HeartbeatResponse"), false),
+					method("AssignRole", qualify("AssignRoleRequest"), qualify("AssignRoleResponse"), false),
+					method("UpdateTrust", qualify("TrustUpdateRequest"), qualify("TrustUpdateResponse"), false),
+					method("RequestSchedule", qualify("ScheduleRequest"), qualify("ScheduleResponse"), false),
+					method("GetClusterState", qualify("ClusterStateRequest"), qualify("ClusterStateResponse"), false),
+					method("WatchClusterState", qualify("ClusterStateRequest"), qualify("ClusterStateResponse"), true),
+				},
+			},
+		},
+	}
+
+	b, err := proto.Marshal(fd)
+	if err != nil {
+		panic("rpc: failed to build orchestrator.proto descriptor: " + err.Error())
+	}
+	return b
+}
+
+func protoHelperBool(b bool) *bool { return &b }
+
+var (
+	file_orchestrator_proto_rawDescOnce sync.Once
+)
+
+var file_orchestrator_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_orchestrator_proto_goTypes = []any{
+	(*JoinRequest)(nil),          // 0: cohesix.orchestrator.JoinRequest
+	(*JoinResponse)(nil),         // 1: cohesix.orchestrator.JoinResponse
+	(*HeartbeatRequest)(nil),     // 2: cohesix.orchestrator.HeartbeatRequest
+	(*HeartbeatResponse)(nil),    // 3: cohesix.orchestrator.HeartbeatResponse
+	(*AssignRoleRequest)(nil),    // 4: cohesix.orchestrator.AssignRoleRequest
+	(*AssignRoleResponse)(nil),   // 5: cohesix.orchestrator.AssignRoleResponse
+	(*TrustUpdateRequest)(nil),   // 6: cohesix.orchestrator.TrustUpdateRequest
+	(*TrustUpdateResponse)(nil),  // 7: cohesix.orchestrator.TrustUpdateResponse
+	(*ScheduleRequest)(nil),      // 8: cohesix.orchestrator.ScheduleRequest
+	(*ScheduleResponse)(nil),     // 9: cohesix.orchestrator.ScheduleResponse
+	(*ClusterStateRequest)(nil),  // 10: cohesix.orchestrator.ClusterStateRequest
+	(*ClusterStateResponse)(nil), // 11: cohesix.orchestrator.ClusterStateResponse
+	(*WorkerState)(nil),          // 12: cohesix.orchestrator.WorkerState
+	(*GpuTelemetry)(nil),         // 13: cohesix.orchestrator.GpuTelemetry
+}
+var file_orchestrator_proto_depIdxs = []int32{
+	12, // 0: cohesix.orchestrator.ClusterStateResponse.workers:type_name -> cohesix.orchestrator.WorkerState
+	13, // 1: cohesix.orchestrator.WorkerState.gpu:type_name -> cohesix.orchestrator.GpuTelemetry
+	0,  // 2: cohesix.orchestrator.OrchestratorService.Join:input_type -> cohesix.orchestrator.JoinRequest
+	2,  // 3: cohesix.orchestrator.OrchestratorService.Heartbeat:input_type -> cohesix.orchestrator.HeartbeatRequest
+	4,  // 4: cohesix.orchestrator.OrchestratorService.AssignRole:input_type -> cohesix.orchestrator.AssignRoleRequest
+	6,  // 5: cohesix.orchestrator.OrchestratorService.UpdateTrust:input_type -> cohesix.orchestrator.TrustUpdateRequest
+	8,  // 6: cohesix.orchestrator.OrchestratorService.RequestSchedule:input_type -> cohesix.orchestrator.ScheduleRequest
+	10, // 7: cohesix.orchestrator.OrchestratorService.GetClusterState:input_type -> cohesix.orchestrator.ClusterStateRequest
+	10, // 8: cohesix.orchestrator.OrchestratorService.WatchClusterState:input_type -> cohesix.orchestrator.ClusterStateRequest
+	1,  // 9: cohesix.orchestrator.OrchestratorService.Join:output_type -> cohesix.orchestrator.JoinResponse
+	3,  // 10: cohesix.orchestrator.OrchestratorService.Heartbeat:output_type -> cohesix.orchestrator.HeartbeatResponse
+	5,  // 11: cohesix.orchestrator.OrchestratorService.AssignRole:output_type -> cohesix.orchestrator.AssignRoleResponse
+	7,  // 12: cohesix.orchestrator.OrchestratorService.UpdateTrust:output_type -> cohesix.orchestrator.TrustUpdateResponse
+	9,  // 13: cohesix.orchestrator.OrchestratorService.RequestSchedule:output_type -> cohesix.orchestrator.ScheduleResponse
+	11, // 14: cohesix.orchestrator.OrchestratorService.GetClusterState:output_type -> cohesix.orchestrator.ClusterStateResponse
+	11, // 15: cohesix.orchestrator.OrchestratorService.WatchClusterState:output_type -> cohesix.orchestrator.ClusterStateResponse
+	9,  // [9:16] is the sub-list for method output_type
+	2,  // [2:9] is the sub-list for method input_type
+	2,  // [2:2] is the sub-list for extension type_name
+	2,  // [2:2] is the sub-list for extension extendee
+	0,  // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_orchestrator_proto_init() }
+func file_orchestrator_proto_init() {
+	if File_orchestrator_proto != nil {
+		return
+	}
+	file_orchestrator_proto_rawDescOnce.Do(func() {})
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_orchestrator_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_orchestrator_proto_goTypes,
+		DependencyIndexes: file_orchestrator_proto_depIdxs,
+		MessageInfos:      file_orchestrator_proto_msgTypes,
+	}.Build()
+	File_orchestrator_proto = out.File
+	file_orchestrator_proto_goTypes = nil
+	file_orchestrator_proto_depIdxs = nil
+}
@@ -0,0 +1,318 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: orchestrator_grpc.pb.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+// See orchestrator.pb.go's package comment: this mirrors protoc-gen-go-grpc
+// output (client stub, server interface, ServiceDesc) by hand, since no
+// protoc-gen-go-grpc binary is available in this build environment.
+package rpc
+
+import (
+	context "context"
+
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	OrchestratorService_Join_FullMethodName               = "/cohesix.orchestrator.OrchestratorService/Join"
+	OrchestratorService_Heartbeat_FullMethodName           = "/cohesix.orchestrator.OrchestratorService/Heartbeat"
+	OrchestratorService_AssignRole_FullMethodName          = "/cohesix.orchestrator.OrchestratorService/AssignRole"
+	OrchestratorService_UpdateTrust_FullMethodName         = "/cohesix.orchestrator.OrchestratorService/UpdateTrust"
+	OrchestratorService_RequestSchedule_FullMethodName     = "/cohesix.orchestrator.OrchestratorService/RequestSchedule"
+	OrchestratorService_GetClusterState_FullMethodName     = "/cohesix.orchestrator.OrchestratorService/GetClusterState"
+	OrchestratorService_WatchClusterState_FullMethodName   = "/cohesix.orchestrator.OrchestratorService/WatchClusterState"
+)
+
+// OrchestratorServiceClient is the client API for OrchestratorService.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please
+// refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type OrchestratorServiceClient interface {
+	Join(ctx context.Context, in *JoinRequest, opts ...grpc.CallOption) (*JoinResponse, error)
+	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
+	AssignRole(ctx context.Context, in *AssignRoleRequest, opts ...grpc.CallOption) (*AssignRoleResponse, error)
+	UpdateTrust(ctx context.Context, in *TrustUpdateRequest, opts ...grpc.CallOption) (*TrustUpdateResponse, error)
+	RequestSchedule(ctx context.Context, in *ScheduleRequest, opts ...grpc.CallOption) (*ScheduleResponse, error)
+	GetClusterState(ctx context.Context, in *ClusterStateRequest, opts ...grpc.CallOption) (*ClusterStateResponse, error)
+	// WatchClusterState streams a ClusterStateResponse every time cluster
+	// membership or worker telemetry changes.
+	WatchClusterState(ctx context.Context, in *ClusterStateRequest, opts ...grpc.CallOption) (OrchestratorService_WatchClusterStateClient, error)
+}
+
+type orchestratorServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewOrchestratorServiceClient returns an OrchestratorServiceClient backed
+// by cc, used as-is by GRPCGateway (orchestrator/api) and the grpc-gateway
+// reverse proxy (orchestrator/http) mounted on top of it.
+func NewOrchestratorServiceClient(cc grpc.ClientConnInterface) OrchestratorServiceClient {
+	return &orchestratorServiceClient{cc}
+}
+
+func (c *orchestratorServiceClient) Join(ctx context.Context, in *JoinRequest, opts ...grpc.CallOption) (*JoinResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JoinResponse)
+	if err := c.cc.Invoke(ctx, OrchestratorService_Join_FullMethodName, in, out, cOpts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HeartbeatResponse)
+	if err := c.cc.Invoke(ctx, OrchestratorService_Heartbeat_FullMethodName, in, out, cOpts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) AssignRole(ctx context.Context, in *AssignRoleRequest, opts ...grpc.CallOption) (*AssignRoleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AssignRoleResponse)
+	if err := c.cc.Invoke(ctx, OrchestratorService_AssignRole_FullMethodName, in, out, cOpts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) UpdateTrust(ctx context.Context, in *TrustUpdateRequest, opts ...grpc.CallOption) (*TrustUpdateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TrustUpdateResponse)
+	if err := c.cc.Invoke(ctx, OrchestratorService_UpdateTrust_FullMethodName, in, out, cOpts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) RequestSchedule(ctx context.Context, in *ScheduleRequest, opts ...grpc.CallOption) (*ScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScheduleResponse)
+	if err := c.cc.Invoke(ctx, OrchestratorService_RequestSchedule_FullMethodName, in, out, cOpts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) GetClusterState(ctx context.Context, in *ClusterStateRequest, opts ...grpc.CallOption) (*ClusterStateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClusterStateResponse)
+	if err := c.cc.Invoke(ctx, OrchestratorService_GetClusterState_FullMethodName, in, out, cOpts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) WatchClusterState(ctx context.Context, in *ClusterStateRequest, opts ...grpc.CallOption) (OrchestratorService_WatchClusterStateClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrchestratorService_ServiceDesc.Streams[0], OrchestratorService_WatchClusterState_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ClusterStateRequest, ClusterStateResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// OrchestratorService_WatchClusterStateClient is the streaming client handle
+// WatchClusterState returns; orchestrator/http's WebSocket bridge (stream.go)
+// calls Recv() on it in a loop.
+type OrchestratorService_WatchClusterStateClient = grpc.ServerStreamingClient[ClusterStateResponse]
+
+// OrchestratorServiceServer is the server API for OrchestratorService.
+// All implementations should embed UnimplementedOrchestratorServer for
+// forward compatibility.
+type OrchestratorServiceServer interface {
+	Join(context.Context, *JoinRequest) (*JoinResponse, error)
+	Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error)
+	AssignRole(context.Context, *AssignRoleRequest) (*AssignRoleResponse, error)
+	UpdateTrust(context.Context, *TrustUpdateRequest) (*TrustUpdateResponse, error)
+	RequestSchedule(context.Context, *ScheduleRequest) (*ScheduleResponse, error)
+	GetClusterState(context.Context, *ClusterStateRequest) (*ClusterStateResponse, error)
+	WatchClusterState(*ClusterStateRequest, OrchestratorService_WatchClusterStateServer) error
+}
+
+// OrchestratorService_WatchClusterStateServer is the streaming server handle
+// a OrchestratorServiceServer implementation sends ClusterStateResponses on.
+type OrchestratorService_WatchClusterStateServer = grpc.ServerStreamingServer[ClusterStateResponse]
+
+// UnimplementedOrchestratorServer should be embedded to have forward
+// compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedOrchestratorServer struct{}
+
+func (UnimplementedOrchestratorServer) Join(context.Context, *JoinRequest) (*JoinResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Join not implemented")
+}
+func (UnimplementedOrchestratorServer) Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Heartbeat not implemented")
+}
+func (UnimplementedOrchestratorServer) AssignRole(context.Context, *AssignRoleRequest) (*AssignRoleResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AssignRole not implemented")
+}
+func (UnimplementedOrchestratorServer) UpdateTrust(context.Context, *TrustUpdateRequest) (*TrustUpdateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateTrust not implemented")
+}
+func (UnimplementedOrchestratorServer) RequestSchedule(context.Context, *ScheduleRequest) (*ScheduleResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RequestSchedule not implemented")
+}
+func (UnimplementedOrchestratorServer) GetClusterState(context.Context, *ClusterStateRequest) (*ClusterStateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetClusterState not implemented")
+}
+func (UnimplementedOrchestratorServer) WatchClusterState(*ClusterStateRequest, OrchestratorService_WatchClusterStateServer) error {
+	return status.Error(codes.Unimplemented, "method WatchClusterState not implemented")
+}
+func (UnimplementedOrchestratorServer) testEmbeddedByValue() {}
+
+// UnsafeOrchestratorServiceServer may be embedded to opt out of forward
+// compatibility for this service. Use of this interface is not recommended.
+type UnsafeOrchestratorServiceServer interface {
+	mustEmbedUnimplementedOrchestratorServer()
+}
+
+// RegisterOrchestratorServiceServer registers srv with s.
+func RegisterOrchestratorServiceServer(s grpc.ServiceRegistrar, srv OrchestratorServiceServer) {
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&OrchestratorService_ServiceDesc, srv)
+}
+
+func _OrchestratorService_Join_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JoinRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).Join(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: OrchestratorService_Join_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).Join(ctx, req.(*JoinRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeartbeatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).Heartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: OrchestratorService_Heartbeat_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).Heartbeat(ctx, req.(*HeartbeatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_AssignRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).AssignRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: OrchestratorService_AssignRole_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).AssignRole(ctx, req.(*AssignRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_UpdateTrust_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TrustUpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).UpdateTrust(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: OrchestratorService_UpdateTrust_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).UpdateTrust(ctx, req.(*TrustUpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_RequestSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).RequestSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: OrchestratorService_RequestSchedule_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).RequestSchedule(ctx, req.(*ScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_GetClusterState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClusterStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).GetClusterState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: OrchestratorService_GetClusterState_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).GetClusterState(ctx, req.(*ClusterStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_WatchClusterState_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ClusterStateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrchestratorServiceServer).WatchClusterState(m, &grpc.GenericServerStream[ClusterStateRequest, ClusterStateResponse]{ServerStream: stream})
+}
+
+// OrchestratorService_ServiceDesc is the grpc.ServiceDesc for
+// OrchestratorService. It's only intended for direct use with
+// grpc.RegisterService, and not to be introspected or modified (even as a
+// copy).
+var OrchestratorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cohesix.orchestrator.OrchestratorService",
+	HandlerType: (*OrchestratorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Join", Handler: _OrchestratorService_Join_Handler},
+		{MethodName: "Heartbeat", Handler: _OrchestratorService_Heartbeat_Handler},
+		{MethodName: "AssignRole", Handler: _OrchestratorService_AssignRole_Handler},
+		{MethodName: "UpdateTrust", Handler: _OrchestratorService_UpdateTrust_Handler},
+		{MethodName: "RequestSchedule", Handler: _OrchestratorService_RequestSchedule_Handler},
+		{MethodName: "GetClusterState", Handler: _OrchestratorService_GetClusterState_Handler},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchClusterState",
+			Handler:       _OrchestratorService_WatchClusterState_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "orchestrator.proto",
+}
@@ -0,0 +1,16 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: swagger.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-25
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package rpc
+
+import _ "embed"
+
+// SwaggerJSON is the OpenAPI/Swagger document generated from
+// orchestrator.proto by protoc-gen-openapiv2, describing the REST surface
+// grpc-gateway exposes for OrchestratorService.
+//
+//go:embed orchestrator.swagger.json
+var SwaggerJSON []byte
@@ -0,0 +1,155 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: etcd.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package storage
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// EtcdOptions configures an etcd-backed Storage.
+type EtcdOptions struct {
+	Endpoints   []string
+	DialTimeout time.Duration
+	// TLS is optional; a nil value dials etcd in plaintext.
+	TLS *tls.Config
+}
+
+// EtcdStorage implements Storage against an etcd v3 cluster. Every key is
+// written under a single prefix, so distinct orchestrator deployments (or
+// environments) sharing an etcd cluster stay isolated by giving each a
+// different Config.StoragePrefix.
+type EtcdStorage struct {
+	client *clientv3.Client
+	prefix string
+}
+
+// NewEtcdStorage dials etcd and returns a Storage scoped under prefix.
+func NewEtcdStorage(ctx context.Context, prefix string, opts EtcdOptions) (*EtcdStorage, error) {
+	if len(opts.Endpoints) == 0 {
+		return nil, fmt.Errorf("etcd storage: at least one endpoint is required")
+	}
+	dialTimeout := opts.DialTimeout
+	if dialTimeout <= 0 {
+		dialTimeout = 5 * time.Second
+	}
+	cli, err := clientv3.New(clientv3.Config{
+		Endpoints:   opts.Endpoints,
+		DialTimeout: dialTimeout,
+		TLS:         opts.TLS,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("etcd storage: dial: %w", err)
+	}
+	return &EtcdStorage{client: cli, prefix: strings.Trim(prefix, "/")}, nil
+}
+
+func (s *EtcdStorage) fullKey(key string) string {
+	return "/" + path.Join(s.prefix, key)
+}
+
+func (s *EtcdStorage) trimPrefix(fullKey string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(fullKey, "/"+s.prefix), "/")
+}
+
+// Get implements Storage.
+func (s *EtcdStorage) Get(ctx context.Context, key string) (Item, error) {
+	resp, err := s.client.Get(ctx, s.fullKey(key))
+	if err != nil {
+		return Item{}, err
+	}
+	if len(resp.Kvs) == 0 {
+		return Item{}, ErrNotFound
+	}
+	kv := resp.Kvs[0]
+	return Item{Key: key, Value: kv.Value, ModRevision: kv.ModRevision}, nil
+}
+
+// Put implements Storage.
+func (s *EtcdStorage) Put(ctx context.Context, key string, value []byte) (int64, error) {
+	resp, err := s.client.Put(ctx, s.fullKey(key), string(value))
+	if err != nil {
+		return 0, err
+	}
+	return resp.Header.Revision, nil
+}
+
+// PutIfUnmodified implements Storage using an etcd transaction guarded by
+// Compare(ModRevision) so two orchestrators racing to write the same key
+// (e.g. a role reassignment) cannot clobber each other.
+func (s *EtcdStorage) PutIfUnmodified(ctx context.Context, key string, value []byte, expectModRevision int64) (int64, error) {
+	full := s.fullKey(key)
+	txn := s.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(full), "=", expectModRevision)).
+		Then(clientv3.OpPut(full, string(value))).
+		Else(clientv3.OpGet(full))
+	resp, err := txn.Commit()
+	if err != nil {
+		return 0, err
+	}
+	if !resp.Succeeded {
+		return 0, ErrConflict
+	}
+	return resp.Header.Revision, nil
+}
+
+// Delete implements Storage.
+func (s *EtcdStorage) Delete(ctx context.Context, key string) error {
+	_, err := s.client.Delete(ctx, s.fullKey(key))
+	return err
+}
+
+// List implements Storage.
+func (s *EtcdStorage) List(ctx context.Context, prefix string) ([]Item, error) {
+	resp, err := s.client.Get(ctx, s.fullKey(prefix), clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	if err != nil {
+		return nil, err
+	}
+	items := make([]Item, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		items = append(items, Item{Key: s.trimPrefix(string(kv.Key)), Value: kv.Value, ModRevision: kv.ModRevision})
+	}
+	return items, nil
+}
+
+// Watch implements Storage, pushing every subsequent change to a key under
+// prefix to the returned channel until ctx is cancelled.
+func (s *EtcdStorage) Watch(ctx context.Context, prefix string) (<-chan Event, error) {
+	wch := s.client.Watch(ctx, s.fullKey(prefix), clientv3.WithPrefix())
+	out := make(chan Event, 8)
+	go func() {
+		defer close(out)
+		for resp := range wch {
+			for _, ev := range resp.Events {
+				item := Item{Key: s.trimPrefix(string(ev.Kv.Key)), ModRevision: ev.Kv.ModRevision}
+				e := Event{Type: EventPut, Item: item}
+				if ev.Type == clientv3.EventTypeDelete {
+					e.Type = EventDelete
+				} else {
+					e.Item.Value = ev.Kv.Value
+				}
+				select {
+				case out <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+// Close closes the underlying etcd client connection.
+func (s *EtcdStorage) Close() error {
+	return s.client.Close()
+}
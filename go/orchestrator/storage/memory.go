@@ -0,0 +1,142 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: memory.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package storage
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemoryStorage is an in-process Storage implementation: the driver Dev
+// mode (and tests) use when there's no etcd cluster to talk to. Every
+// Put/Delete advances a single monotonic revision counter shared across all
+// keys, the way etcd's own ModRevision does.
+type MemoryStorage struct {
+	mu       sync.Mutex
+	items    map[string]Item
+	revision int64
+
+	watchMu  sync.Mutex
+	watchers map[chan Event]string
+}
+
+// NewMemoryStorage returns an empty MemoryStorage.
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		items:    make(map[string]Item),
+		watchers: make(map[chan Event]string),
+	}
+}
+
+// Get implements Storage.
+func (s *MemoryStorage) Get(ctx context.Context, key string) (Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item, ok := s.items[key]
+	if !ok {
+		return Item{}, ErrNotFound
+	}
+	return item, nil
+}
+
+// Put implements Storage.
+func (s *MemoryStorage) Put(ctx context.Context, key string, value []byte) (int64, error) {
+	s.mu.Lock()
+	s.revision++
+	item := Item{Key: key, Value: value, ModRevision: s.revision}
+	s.items[key] = item
+	s.mu.Unlock()
+	s.notify(Event{Type: EventPut, Item: item})
+	return item.ModRevision, nil
+}
+
+// PutIfUnmodified implements Storage.
+func (s *MemoryStorage) PutIfUnmodified(ctx context.Context, key string, value []byte, expectModRevision int64) (int64, error) {
+	s.mu.Lock()
+	current, ok := s.items[key]
+	switch {
+	case !ok && expectModRevision != 0:
+		s.mu.Unlock()
+		return 0, ErrConflict
+	case ok && current.ModRevision != expectModRevision:
+		s.mu.Unlock()
+		return 0, ErrConflict
+	}
+	s.revision++
+	item := Item{Key: key, Value: value, ModRevision: s.revision}
+	s.items[key] = item
+	s.mu.Unlock()
+	s.notify(Event{Type: EventPut, Item: item})
+	return item.ModRevision, nil
+}
+
+// Delete implements Storage.
+func (s *MemoryStorage) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	_, ok := s.items[key]
+	if !ok {
+		s.mu.Unlock()
+		return nil
+	}
+	delete(s.items, key)
+	s.revision++
+	rev := s.revision
+	s.mu.Unlock()
+	s.notify(Event{Type: EventDelete, Item: Item{Key: key, ModRevision: rev}})
+	return nil
+}
+
+// List implements Storage.
+func (s *MemoryStorage) List(ctx context.Context, prefix string) ([]Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items := make([]Item, 0, len(s.items))
+	for key, item := range s.items {
+		if strings.HasPrefix(key, prefix) {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Key < items[j].Key })
+	return items, nil
+}
+
+// Watch implements Storage.
+func (s *MemoryStorage) Watch(ctx context.Context, prefix string) (<-chan Event, error) {
+	ch := make(chan Event, 8)
+	s.watchMu.Lock()
+	s.watchers[ch] = prefix
+	s.watchMu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		s.watchMu.Lock()
+		delete(s.watchers, ch)
+		s.watchMu.Unlock()
+		close(ch)
+	}()
+	return ch, nil
+}
+
+// Close implements Storage. MemoryStorage holds no external resources, so
+// Close is a no-op kept only to satisfy the interface.
+func (s *MemoryStorage) Close() error { return nil }
+
+func (s *MemoryStorage) notify(ev Event) {
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+	for ch, prefix := range s.watchers {
+		if !strings.HasPrefix(ev.Item.Key, prefix) {
+			continue
+		}
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: memory_test.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoragePutGet(t *testing.T) {
+	s := NewMemoryStorage()
+	rev, err := s.Put(context.Background(), "role/worker-a", []byte("QueenPrimary"))
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if rev == 0 {
+		t.Fatal("expected a non-zero revision")
+	}
+	item, err := s.Get(context.Background(), "role/worker-a")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if string(item.Value) != "QueenPrimary" || item.ModRevision != rev {
+		t.Fatalf("unexpected item: %+v", item)
+	}
+}
+
+func TestMemoryStorageGetNotFound(t *testing.T) {
+	s := NewMemoryStorage()
+	if _, err := s.Get(context.Background(), "missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestMemoryStoragePutIfUnmodified(t *testing.T) {
+	s := NewMemoryStorage()
+	rev, err := s.Put(context.Background(), "role/worker-a", []byte("DroneWorker"))
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	if _, err := s.PutIfUnmodified(context.Background(), "role/worker-a", []byte("QueenPrimary"), rev+1); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict on stale revision, got %v", err)
+	}
+
+	newRev, err := s.PutIfUnmodified(context.Background(), "role/worker-a", []byte("QueenPrimary"), rev)
+	if err != nil {
+		t.Fatalf("cas put: %v", err)
+	}
+	item, err := s.Get(context.Background(), "role/worker-a")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if string(item.Value) != "QueenPrimary" || item.ModRevision != newRev {
+		t.Fatalf("unexpected item after cas: %+v", item)
+	}
+}
+
+func TestMemoryStoragePutIfUnmodifiedRequiresAbsence(t *testing.T) {
+	s := NewMemoryStorage()
+	if _, err := s.PutIfUnmodified(context.Background(), "new-key", []byte("v"), 5); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict for nonzero expectation on an absent key, got %v", err)
+	}
+	if _, err := s.PutIfUnmodified(context.Background(), "new-key", []byte("v"), 0); err != nil {
+		t.Fatalf("expected create-if-absent to succeed, got %v", err)
+	}
+}
+
+func TestMemoryStorageDelete(t *testing.T) {
+	s := NewMemoryStorage()
+	s.Put(context.Background(), "role/worker-a", []byte("x"))
+	if err := s.Delete(context.Background(), "role/worker-a"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := s.Get(context.Background(), "role/worker-a"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound after delete, got %v", err)
+	}
+	if err := s.Delete(context.Background(), "role/worker-a"); err != nil {
+		t.Fatalf("deleting an absent key should not error, got %v", err)
+	}
+}
+
+func TestMemoryStorageList(t *testing.T) {
+	s := NewMemoryStorage()
+	s.Put(context.Background(), "role/worker-a", []byte("a"))
+	s.Put(context.Background(), "role/worker-b", []byte("b"))
+	s.Put(context.Background(), "trust/worker-a", []byte("green"))
+
+	items, err := s.List(context.Background(), "role/")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(items) != 2 || items[0].Key != "role/worker-a" || items[1].Key != "role/worker-b" {
+		t.Fatalf("unexpected list result: %+v", items)
+	}
+}
+
+func TestMemoryStorageWatch(t *testing.T) {
+	s := NewMemoryStorage()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := s.Watch(ctx, "role/")
+	if err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+
+	s.Put(context.Background(), "role/worker-a", []byte("QueenPrimary"))
+	select {
+	case ev := <-events:
+		if ev.Type != EventPut || ev.Item.Key != "role/worker-a" {
+			t.Fatalf("unexpected event: %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for put event")
+	}
+
+	s.Delete(context.Background(), "role/worker-a")
+	select {
+	case ev := <-events:
+		if ev.Type != EventDelete || ev.Item.Key != "role/worker-a" {
+			t.Fatalf("unexpected event: %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for delete event")
+	}
+}
@@ -0,0 +1,83 @@
+// CLASSIFICATION: COMMUNITY
+// Filename: storage.go v0.1
+// Author: Lukas Bower
+// Date Modified: 2026-07-26
+// License: SPDX-License-Identifier: MIT OR Apache-2.0
+
+// Package storage gives the orchestrator's control state a home other than
+// implicit in-memory fields on Server: a versioned key/value abstraction
+// that Server.controller reads and writes through, backed either by an
+// in-memory driver (Dev mode, tests) or an etcd v3 cluster so role
+// assignments, trust updates, and schedule decisions survive a restart and
+// stay consistent across multiple orchestrator instances.
+package storage
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned by Get when key has no value.
+var ErrNotFound = errors.New("storage: key not found")
+
+// ErrConflict is returned by PutIfUnmodified when key's current
+// ModRevision does not match the caller's expectation.
+var ErrConflict = errors.New("storage: modrevision conflict")
+
+// Item is a versioned value stored under a key.
+type Item struct {
+	Key         string
+	Value       []byte
+	ModRevision int64
+}
+
+// EventType distinguishes a write from a delete in a Watch stream.
+type EventType int
+
+const (
+	// EventPut is emitted when a key is created or overwritten.
+	EventPut EventType = iota
+	// EventDelete is emitted when a key is removed.
+	EventDelete
+)
+
+// Event describes a single change observed by Watch.
+type Event struct {
+	Type EventType
+	Item Item
+}
+
+// Storage is a namespaced, revision-scoped key/value store. The etcd driver
+// scopes its keys under its configured prefix (see etcd.go) and the memory
+// driver keeps one unprefixed namespace, so callers pass keys relative to
+// the driver, e.g. "role/worker-a".
+type Storage interface {
+	// Get returns the current value and revision for key.
+	Get(ctx context.Context, key string) (Item, error)
+
+	// Put unconditionally writes value under key and returns the new
+	// ModRevision.
+	Put(ctx context.Context, key string, value []byte) (int64, error)
+
+	// PutIfUnmodified writes value under key only if key's current
+	// ModRevision equals expectModRevision, using a transaction so
+	// concurrent writers (e.g. two controllers racing to reassign the same
+	// worker's role) cannot silently clobber each other. Pass
+	// expectModRevision 0 to require that key does not yet exist. Returns
+	// ErrConflict on mismatch.
+	PutIfUnmodified(ctx context.Context, key string, value []byte, expectModRevision int64) (int64, error)
+
+	// Delete removes key. It is not an error if key does not exist.
+	Delete(ctx context.Context, key string) error
+
+	// List returns every Item whose key has prefix, ordered by key.
+	List(ctx context.Context, prefix string) ([]Item, error)
+
+	// Watch streams Events for keys under prefix until ctx is cancelled.
+	// The returned channel is closed once ctx is done.
+	Watch(ctx context.Context, prefix string) (<-chan Event, error)
+
+	// Close releases any resources (connections, leases) held by the
+	// Storage.
+	Close() error
+}